@@ -0,0 +1,235 @@
+// Package backup saves the contents of a gator database to a file and
+// restores it again. The file format does not depend on the SQL backend, so
+// a backup taken from Postgres can be restored into SQLite and vice versa.
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Format identifies gator backup files.
+const Format = "gator-backup"
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Version is the version of the file format written by this gator. Newer
+// versions may add fields; files with a higher version are rejected.
+//
+//  1. The first format.
+//  2. Adds user roles, who added each feed and the feed fetch schedule.
+const Version = 2
+
+// Backup is the contents of a database. Rows are listed in the order they
+// must be restored so that references are always satisfied.
+type Backup struct {
+	Format            string            `json:"format"`
+	Version           int               `json:"version"`
+	CreatedAt         time.Time         `json:"created_at"`
+	Users             []User            `json:"users"`
+	Feeds             []Feed            `json:"feeds"`
+	Folders           []Folder          `json:"folders"`
+	FeedFollows       []FeedFollow      `json:"feed_follows"`
+	FilterRules       []FilterRule      `json:"filter_rules"`
+	Webhooks          []Webhook         `json:"webhooks"`
+	Posts             []Post            `json:"posts"`
+	PostCategories    []PostCategory    `json:"post_categories"`
+	Enclosures        []Enclosure       `json:"enclosures"`
+	WebhookDeliveries []WebhookDelivery `json:"webhook_deliveries"`
+}
+
+type User struct {
+	ID           uuid.UUID  `json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	Name         string     `json:"name"`
+	Email        *string    `json:"email,omitempty"`
+	LastDigestAt *time.Time `json:"last_digest_at,omitempty"`
+	Role         string     `json:"role,omitempty"`
+}
+
+type Feed struct {
+	ID                uuid.UUID  `json:"id"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	Name              string     `json:"name"`
+	URL               string     `json:"url"`
+	LastFetchedAt     *time.Time `json:"last_fetched_at,omitempty"`
+	IsPodcast         bool       `json:"is_podcast"`
+	AutoDownload      bool       `json:"auto_download"`
+	LastFetchStatus   *string    `json:"last_fetch_status,omitempty"`
+	LastFetchError    *string    `json:"last_fetch_error,omitempty"`
+	LastFetchNewPosts *int32     `json:"last_fetch_new_posts,omitempty"`
+	AddedBy           *uuid.UUID `json:"added_by,omitempty"`
+	NextFetchAt       *time.Time `json:"next_fetch_at,omitempty"`
+	FetchInterval     *int32     `json:"fetch_interval_seconds,omitempty"`
+}
+
+type Folder struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+}
+
+type FeedFollow struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	UserID    uuid.UUID  `json:"user_id"`
+	FeedID    uuid.UUID  `json:"feed_id"`
+	FolderID  *uuid.UUID `json:"folder_id,omitempty"`
+}
+
+type FilterRule struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Action    string     `json:"action"`
+	Field     string     `json:"field"`
+	Pattern   string     `json:"pattern"`
+	IsRegex   bool       `json:"is_regex"`
+	FeedID    *uuid.UUID `json:"feed_id,omitempty"`
+}
+
+type Webhook struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	UserID    uuid.UUID  `json:"user_id"`
+	URL       string     `json:"url"`
+	Secret    *string    `json:"secret,omitempty"`
+	Format    string     `json:"format"`
+	Batch     bool       `json:"batch"`
+	FeedID    *uuid.UUID `json:"feed_id,omitempty"`
+	Keyword   *string    `json:"keyword,omitempty"`
+}
+
+type Post struct {
+	ID          uuid.UUID  `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Description *string    `json:"description,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	FeedID      uuid.UUID  `json:"feed_id"`
+	Author      *string    `json:"author,omitempty"`
+}
+
+type PostCategory struct {
+	PostID uuid.UUID `json:"post_id"`
+	Name   string    `json:"name"`
+}
+
+type Enclosure struct {
+	ID              uuid.UUID  `json:"id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	PostID          uuid.UUID  `json:"post_id"`
+	URL             string     `json:"url"`
+	MimeType        string     `json:"mime_type"`
+	Length          *int64     `json:"length,omitempty"`
+	DurationSeconds *int32     `json:"duration_seconds,omitempty"`
+	ImageURL        *string    `json:"image_url,omitempty"`
+	LocalPath       *string    `json:"local_path,omitempty"`
+	DownloadedBytes *int64     `json:"downloaded_bytes,omitempty"`
+	Sha256          *string    `json:"sha256,omitempty"`
+	DownloadedAt    *time.Time `json:"downloaded_at,omitempty"`
+	PlayedAt        *time.Time `json:"played_at,omitempty"`
+}
+
+type WebhookDelivery struct {
+	ID         uuid.UUID  `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	WebhookID  uuid.UUID  `json:"webhook_id"`
+	PostID     *uuid.UUID `json:"post_id,omitempty"`
+	PostCount  int32      `json:"post_count"`
+	Attempts   int32      `json:"attempts"`
+	StatusCode *int32     `json:"status_code,omitempty"`
+	Error      *string    `json:"error,omitempty"`
+	Delivered  bool       `json:"delivered"`
+}
+
+// Write saves b to path, compressed with gzip if path ends in .gz. The file
+// is readable only by the current user since backups contain email
+// addresses and webhook secrets.
+func Write(path string, b *Backup) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = file
+	var compressor *gzip.Writer
+	if strings.HasSuffix(path, ".gz") {
+		compressor = gzip.NewWriter(file)
+		w = compressor
+	}
+
+	err = Encode(w, b)
+	if err == nil && compressor != nil {
+		err = compressor.Close()
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Encode writes b to w as indented JSON.
+func Encode(w io.Writer, b *Backup) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(b)
+}
+
+// Read loads the backup saved at path, which may be gzip compressed.
+func Read(path string) (*Backup, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	buffered := bufio.NewReader(file)
+	var r io.Reader = buffered
+	magic, _ := buffered.Peek(2)
+	if bytes.Equal(magic, gzipMagic) {
+		decompressor, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		defer decompressor.Close()
+		r = decompressor
+	}
+
+	var b Backup
+	err = json.NewDecoder(r).Decode(&b)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a gator backup: %w", path, err)
+	}
+
+	if b.Format != Format {
+		return nil, fmt.Errorf("%s is not a gator backup", path)
+	}
+	if b.Version > Version {
+		return nil, fmt.Errorf("%s was written by a newer gator (format version %d, this gator reads up to %d)", path, b.Version, Version)
+	}
+
+	return &b, nil
+}
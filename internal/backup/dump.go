@@ -0,0 +1,185 @@
+package backup
+
+import (
+	"context"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+)
+
+// Dump reads every row of the database into a Backup.
+func Dump(ctx context.Context, q *database.Queries) (*Backup, error) {
+	b := &Backup{
+		Format:    Format,
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	users, err := q.DumpUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		b.Users = append(b.Users, User{
+			ID:           user.ID,
+			CreatedAt:    user.CreatedAt,
+			UpdatedAt:    user.UpdatedAt,
+			Name:         user.Name,
+			Email:        stringPtr(user.Email),
+			LastDigestAt: timePtr(user.LastDigestAt),
+			Role:         user.Role,
+		})
+	}
+
+	feeds, err := q.DumpFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, feed := range feeds {
+		b.Feeds = append(b.Feeds, Feed{
+			ID:                feed.ID,
+			CreatedAt:         feed.CreatedAt,
+			UpdatedAt:         feed.UpdatedAt,
+			Name:              feed.Name,
+			URL:               feed.Url,
+			LastFetchedAt:     timePtr(feed.LastFetchedAt),
+			IsPodcast:         feed.IsPodcast,
+			AutoDownload:      feed.AutoDownload,
+			LastFetchStatus:   stringPtr(feed.LastFetchStatus),
+			LastFetchError:    stringPtr(feed.LastFetchError),
+			LastFetchNewPosts: int32Ptr(feed.LastFetchNewPosts),
+			AddedBy:           uuidPtr(feed.AddedBy),
+			NextFetchAt:       timePtr(feed.NextFetchAt),
+			FetchInterval:     int32Ptr(feed.FetchIntervalSeconds),
+		})
+	}
+
+	folders, err := q.DumpFolders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range folders {
+		b.Folders = append(b.Folders, Folder(folder))
+	}
+
+	follows, err := q.DumpFeedFollows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, follow := range follows {
+		b.FeedFollows = append(b.FeedFollows, FeedFollow{
+			ID:        follow.ID,
+			CreatedAt: follow.CreatedAt,
+			UpdatedAt: follow.UpdatedAt,
+			UserID:    follow.UserID,
+			FeedID:    follow.FeedID,
+			FolderID:  uuidPtr(follow.FolderID),
+		})
+	}
+
+	rules, err := q.DumpFilterRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		b.FilterRules = append(b.FilterRules, FilterRule{
+			ID:        rule.ID,
+			CreatedAt: rule.CreatedAt,
+			UpdatedAt: rule.UpdatedAt,
+			UserID:    rule.UserID,
+			Action:    rule.Action,
+			Field:     rule.Field,
+			Pattern:   rule.Pattern,
+			IsRegex:   rule.IsRegex,
+			FeedID:    uuidPtr(rule.FeedID),
+		})
+	}
+
+	webhooks, err := q.DumpWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		b.Webhooks = append(b.Webhooks, Webhook{
+			ID:        webhook.ID,
+			CreatedAt: webhook.CreatedAt,
+			UpdatedAt: webhook.UpdatedAt,
+			UserID:    webhook.UserID,
+			URL:       webhook.Url,
+			Secret:    stringPtr(webhook.Secret),
+			Format:    webhook.Format,
+			Batch:     webhook.Batch,
+			FeedID:    uuidPtr(webhook.FeedID),
+			Keyword:   stringPtr(webhook.Keyword),
+		})
+	}
+
+	posts, err := q.DumpPosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, post := range posts {
+		b.Posts = append(b.Posts, Post{
+			ID:          post.ID,
+			CreatedAt:   post.CreatedAt,
+			UpdatedAt:   post.UpdatedAt,
+			Title:       post.Title,
+			URL:         post.Url,
+			Description: stringPtr(post.Description),
+			PublishedAt: timePtr(post.PublishedAt),
+			FeedID:      post.FeedID,
+			Author:      stringPtr(post.Author),
+		})
+	}
+
+	categories, err := q.DumpPostCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		b.PostCategories = append(b.PostCategories, PostCategory(category))
+	}
+
+	enclosures, err := q.DumpEnclosures(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, enclosure := range enclosures {
+		b.Enclosures = append(b.Enclosures, Enclosure{
+			ID:              enclosure.ID,
+			CreatedAt:       enclosure.CreatedAt,
+			UpdatedAt:       enclosure.UpdatedAt,
+			PostID:          enclosure.PostID,
+			URL:             enclosure.Url,
+			MimeType:        enclosure.MimeType,
+			Length:          int64Ptr(enclosure.Length),
+			DurationSeconds: int32Ptr(enclosure.DurationSeconds),
+			ImageURL:        stringPtr(enclosure.ImageUrl),
+			LocalPath:       stringPtr(enclosure.LocalPath),
+			DownloadedBytes: int64Ptr(enclosure.DownloadedBytes),
+			Sha256:          stringPtr(enclosure.Sha256),
+			DownloadedAt:    timePtr(enclosure.DownloadedAt),
+			PlayedAt:        timePtr(enclosure.PlayedAt),
+		})
+	}
+
+	deliveries, err := q.DumpWebhookDeliveries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, delivery := range deliveries {
+		b.WebhookDeliveries = append(b.WebhookDeliveries, WebhookDelivery{
+			ID:         delivery.ID,
+			CreatedAt:  delivery.CreatedAt,
+			WebhookID:  delivery.WebhookID,
+			PostID:     uuidPtr(delivery.PostID),
+			PostCount:  delivery.PostCount,
+			Attempts:   delivery.Attempts,
+			StatusCode: int32Ptr(delivery.StatusCode),
+			Error:      stringPtr(delivery.Error),
+			Delivered:  delivery.Delivered,
+		})
+	}
+
+	return b, nil
+}
@@ -0,0 +1,218 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ctiller15/gator/internal/database"
+)
+
+// Restore writes the rows of b into the database, keeping their IDs. Rows
+// that already exist are updated, so restoring over a partly reset database
+// brings back what was deleted. Run it in a transaction so that a failed
+// restore leaves the database unchanged.
+func Restore(ctx context.Context, q *database.Queries, b *Backup) error {
+	err := checkConflicts(ctx, q, b)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range b.Users {
+		// Backups written before roles existed restore as members.
+		role := user.Role
+		if role == "" {
+			role = "member"
+		}
+
+		err = q.RestoreUser(ctx, database.RestoreUserParams{
+			ID:           user.ID,
+			CreatedAt:    user.CreatedAt,
+			UpdatedAt:    user.UpdatedAt,
+			Name:         user.Name,
+			Email:        nullString(user.Email),
+			LastDigestAt: nullTime(user.LastDigestAt),
+			Role:         role,
+		})
+		if err != nil {
+			return fmt.Errorf("restoring user %s: %w", user.Name, err)
+		}
+	}
+
+	for _, feed := range b.Feeds {
+		err = q.RestoreFeed(ctx, database.RestoreFeedParams{
+			ID:                   feed.ID,
+			CreatedAt:            feed.CreatedAt,
+			UpdatedAt:            feed.UpdatedAt,
+			Name:                 feed.Name,
+			Url:                  feed.URL,
+			LastFetchedAt:        nullTime(feed.LastFetchedAt),
+			IsPodcast:            feed.IsPodcast,
+			AutoDownload:         feed.AutoDownload,
+			LastFetchStatus:      nullString(feed.LastFetchStatus),
+			LastFetchError:       nullString(feed.LastFetchError),
+			LastFetchNewPosts:    nullInt32(feed.LastFetchNewPosts),
+			AddedBy:              nullUUID(feed.AddedBy),
+			NextFetchAt:          nullTime(feed.NextFetchAt),
+			FetchIntervalSeconds: nullInt32(feed.FetchInterval),
+		})
+		if err != nil {
+			return fmt.Errorf("restoring feed %s: %w", feed.URL, err)
+		}
+	}
+
+	for _, folder := range b.Folders {
+		err := q.RestoreFolder(ctx, database.RestoreFolderParams(folder))
+		if err != nil {
+			return fmt.Errorf("restoring folder %s: %w", folder.Name, err)
+		}
+	}
+
+	for _, follow := range b.FeedFollows {
+		err := q.RestoreFeedFollow(ctx, database.RestoreFeedFollowParams{
+			ID:        follow.ID,
+			CreatedAt: follow.CreatedAt,
+			UpdatedAt: follow.UpdatedAt,
+			UserID:    follow.UserID,
+			FeedID:    follow.FeedID,
+			FolderID:  nullUUID(follow.FolderID),
+		})
+		if err != nil {
+			return fmt.Errorf("restoring feed follow %s: %w", follow.ID, err)
+		}
+	}
+
+	for _, rule := range b.FilterRules {
+		err := q.RestoreFilterRule(ctx, database.RestoreFilterRuleParams{
+			ID:        rule.ID,
+			CreatedAt: rule.CreatedAt,
+			UpdatedAt: rule.UpdatedAt,
+			UserID:    rule.UserID,
+			Action:    rule.Action,
+			Field:     rule.Field,
+			Pattern:   rule.Pattern,
+			IsRegex:   rule.IsRegex,
+			FeedID:    nullUUID(rule.FeedID),
+		})
+		if err != nil {
+			return fmt.Errorf("restoring filter rule %s: %w", rule.ID, err)
+		}
+	}
+
+	for _, webhook := range b.Webhooks {
+		err := q.RestoreWebhook(ctx, database.RestoreWebhookParams{
+			ID:        webhook.ID,
+			CreatedAt: webhook.CreatedAt,
+			UpdatedAt: webhook.UpdatedAt,
+			UserID:    webhook.UserID,
+			Url:       webhook.URL,
+			Secret:    nullString(webhook.Secret),
+			Format:    webhook.Format,
+			Batch:     webhook.Batch,
+			FeedID:    nullUUID(webhook.FeedID),
+			Keyword:   nullString(webhook.Keyword),
+		})
+		if err != nil {
+			return fmt.Errorf("restoring webhook %s: %w", webhook.URL, err)
+		}
+	}
+
+	for _, post := range b.Posts {
+		err := q.RestorePost(ctx, database.RestorePostParams{
+			ID:          post.ID,
+			CreatedAt:   post.CreatedAt,
+			UpdatedAt:   post.UpdatedAt,
+			Title:       post.Title,
+			Url:         post.URL,
+			Description: nullString(post.Description),
+			PublishedAt: nullTime(post.PublishedAt),
+			FeedID:      post.FeedID,
+			Author:      nullString(post.Author),
+		})
+		if err != nil {
+			return fmt.Errorf("restoring post %s: %w", post.URL, err)
+		}
+	}
+
+	for _, category := range b.PostCategories {
+		err := q.RestorePostCategory(ctx, database.RestorePostCategoryParams(category))
+		if err != nil {
+			return fmt.Errorf("restoring category %s of post %s: %w", category.Name, category.PostID, err)
+		}
+	}
+
+	for _, enclosure := range b.Enclosures {
+		err := q.RestoreEnclosure(ctx, database.RestoreEnclosureParams{
+			ID:              enclosure.ID,
+			CreatedAt:       enclosure.CreatedAt,
+			UpdatedAt:       enclosure.UpdatedAt,
+			PostID:          enclosure.PostID,
+			Url:             enclosure.URL,
+			MimeType:        enclosure.MimeType,
+			Length:          nullInt64(enclosure.Length),
+			DurationSeconds: nullInt32(enclosure.DurationSeconds),
+			ImageUrl:        nullString(enclosure.ImageURL),
+			LocalPath:       nullString(enclosure.LocalPath),
+			DownloadedBytes: nullInt64(enclosure.DownloadedBytes),
+			Sha256:          nullString(enclosure.Sha256),
+			DownloadedAt:    nullTime(enclosure.DownloadedAt),
+			PlayedAt:        nullTime(enclosure.PlayedAt),
+		})
+		if err != nil {
+			return fmt.Errorf("restoring enclosure %s: %w", enclosure.URL, err)
+		}
+	}
+
+	for _, delivery := range b.WebhookDeliveries {
+		err := q.RestoreWebhookDelivery(ctx, database.RestoreWebhookDeliveryParams{
+			ID:         delivery.ID,
+			CreatedAt:  delivery.CreatedAt,
+			WebhookID:  delivery.WebhookID,
+			PostID:     nullUUID(delivery.PostID),
+			PostCount:  delivery.PostCount,
+			Attempts:   delivery.Attempts,
+			StatusCode: nullInt32(delivery.StatusCode),
+			Error:      nullString(delivery.Error),
+			Delivered:  delivery.Delivered,
+		})
+		if err != nil {
+			return fmt.Errorf("restoring webhook delivery %s: %w", delivery.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// checkConflicts rejects a backup whose users or feeds clash with different
+// rows of the same name or url. Rows are restored by ID, so such a clash
+// would otherwise surface as a bare unique constraint error halfway through.
+func checkConflicts(ctx context.Context, q *database.Queries, b *Backup) error {
+	for _, user := range b.Users {
+		existing, err := q.GetUser(ctx, user.Name)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.ID != user.ID {
+			return fmt.Errorf("user %s already exists and is not the one in the backup; rename or delete it before restoring", user.Name)
+		}
+	}
+
+	for _, feed := range b.Feeds {
+		existing, err := q.GetFeedByUrl(ctx, feed.URL)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.ID != feed.ID {
+			return fmt.Errorf("feed %s already exists and is not the one in the backup; change its url with feed seturl or delete it before restoring", feed.URL)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ctiller15/gator/internal/database"
+)
+
+func handlerAdmin(s *state, cmd command, user database.User) error {
+	if len(cmd.args) < 2 {
+		return fmt.Errorf("usage: admin grant|revoke <username>")
+	}
+
+	switch cmd.args[0] {
+	case "grant":
+		return setUserRole(s, cmd.args[1], roleAdmin)
+	case "revoke":
+		return setUserRole(s, cmd.args[1], roleMember)
+	default:
+		return fmt.Errorf("unknown admin command %s", cmd.args[0])
+	}
+}
+
+func setUserRole(s *state, name string, role string) error {
+	ctx := context.Background()
+
+	user, err := s.store.GetUser(ctx, name)
+	if err != nil {
+		return fmt.Errorf("no user named %s", name)
+	}
+	if user.Role == role {
+		fmt.Printf("user %s is already %s\n", user.Name, roleDescription(role))
+		return nil
+	}
+
+	err = checkNotLastAdmin(ctx, s, user)
+	if err != nil {
+		return err
+	}
+
+	setUserRoleParams := database.SetUserRoleParams{
+		Name: user.Name,
+		Role: role,
+	}
+	_, err = s.store.SetUserRole(ctx, setUserRoleParams)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("user %s is now %s\n", user.Name, roleDescription(role))
+	return nil
+}
+
+// checkNotLastAdmin refuses to take the admin role away from user, by
+// revoking it or deleting them, when no other admin could grant it again.
+func checkNotLastAdmin(ctx context.Context, s *state, user database.User) error {
+	if user.Role != roleAdmin {
+		return nil
+	}
+
+	admins, err := s.store.CountUsersWithRole(ctx, roleAdmin)
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return fmt.Errorf("%s is the only admin, grant another user admin first", user.Name)
+	}
+
+	return nil
+}
+
+func roleDescription(role string) string {
+	if role == roleAdmin {
+		return "an admin"
+	}
+
+	return "a member"
+}
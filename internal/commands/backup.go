@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ctiller15/gator/internal/backup"
+	"github.com/ctiller15/gator/internal/database"
+)
+
+func handlerBackup(s *state, cmd command) error {
+	ctx := context.Background()
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	out := fs.String("out", "", "file to write, compressed if it ends in .gz (default: a new file in the backup directory)")
+	_, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return err
+	}
+
+	path := *out
+	if path == "" {
+		backupDir, err := s.cfg.BackupDirectory()
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(backupDir, 0700)
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(backupDir, fmt.Sprintf("gator-%s.json.gz", time.Now().Format("20060102-150405")))
+	}
+
+	// Reading every table in one transaction gives a consistent snapshot
+	// even while agg is adding posts.
+	var b *backup.Backup
+	err = s.conn.InTx(ctx, func(q *database.Queries) error {
+		b, err = backup.Dump(ctx, q)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = backup.Write(path, b)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("saved %d users, %d feeds, %d follows and %d posts to %s\n", len(b.Users), len(b.Feeds), len(b.FeedFollows), len(b.Posts), path)
+	return nil
+}
+
+func handlerRestore(s *state, cmd command) error {
+	ctx := context.Background()
+
+	if len(cmd.args) < 1 {
+		return fmt.Errorf("must provide a backup file")
+	}
+
+	b, err := backup.Read(cmd.args[0])
+	if err != nil {
+		return err
+	}
+
+	err = s.conn.InTx(ctx, func(q *database.Queries) error {
+		return backup.Restore(ctx, q, b)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("restored %d users, %d feeds, %d follows and %d posts from %s\n", len(b.Users), len(b.Feeds), len(b.FeedFollows), len(b.Posts), cmd.args[0])
+	return nil
+}
@@ -3,20 +3,30 @@ package commands
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ctiller15/gator/internal/config"
 	"github.com/ctiller15/gator/internal/database"
+	"github.com/ctiller15/gator/internal/migrate"
 	"github.com/ctiller15/gator/internal/rss"
+	"github.com/ctiller15/gator/internal/storage"
 	"github.com/google/uuid"
-	"github.com/lib/pq"
 )
 
+// state is shared by all handlers. store holds users, feeds, follows and
+// posts. db and conn reach the SQL database for everything else, such as
+// folders, filters, webhooks and enclosures; they are nil when store is not
+// backed by SQL, e.g. a storage.Memory.
 type state struct {
-	db  *database.Queries
-	cfg *config.Config
+	store storage.Store
+	db    *database.Queries
+	conn  *storage.DB
+	cfg   *config.Config
 }
 
 type command struct {
@@ -24,6 +34,66 @@ type command struct {
 	args []string
 }
 
+// Fetch results stored in feeds.last_fetch_status.
+const (
+	fetchStatusOK      = "ok"
+	fetchStatusError   = "error"
+	fetchStatusBlocked = "blocked"
+)
+
+// schemaExempt lists the commands that run without an up-to-date schema.
+var schemaExempt = map[string]bool{
+	"config":  true,
+	"migrate": true,
+}
+
+// sqlCommands lists the commands that need tables outside storage.Store, such
+// as folders, filters, webhooks and enclosures, or the SQL connection itself.
+// They fail with storage.ErrNotSupported when the state has no SQL database.
+var sqlCommands = map[string]bool{
+	"reset":    true,
+	"folder":   true,
+	"filter":   true,
+	"webhook":  true,
+	"digest":   true,
+	"episodes": true,
+	"download": true,
+	"tui":      true,
+	"migrate":  true,
+	"backup":   true,
+	"restore":  true,
+}
+
+// User roles stored in users.role. The first user to register becomes an
+// admin; everyone after is a member until an admin grants them the role.
+const (
+	roleAdmin  = "admin"
+	roleMember = "member"
+)
+
+// commandRoles lists the commands that only some users may run, with the
+// role they require. Commands missing from it are open to everyone.
+var commandRoles = map[string]string{
+	"admin":   roleAdmin,
+	"reset":   roleAdmin,
+	"users":   roleAdmin,
+	"backup":  roleAdmin,
+	"restore": roleAdmin,
+	"migrate": roleAdmin,
+}
+
+// setupCommands lists the commands in commandRoles that anyone may run while
+// no user could log in yet, so a fresh install can create its schema and be
+// restored from a backup without registering a throwaway admin first.
+var setupCommands = map[string]bool{
+	"migrate": true,
+	"restore": true,
+}
+
+// userRolesVersion is the schema version that added users.role
+// (015_user_roles.sql). Older schemas have no admins to check for.
+const userRolesVersion = 15
+
 type commands struct {
 	commandMap map[string]func(*state, command) error
 }
@@ -46,21 +116,43 @@ func NewCommands() *commands {
 	newCommands.register("register", handlerRegister)
 	newCommands.register("reset", handlerReset)
 	newCommands.register("users", handlerGetUsers)
+	newCommands.register("user", handlerUser)
 	newCommands.register("agg", handlerAggregation)
 	newCommands.register("addfeed", middlewareLoggedIn(handlerAddFeed))
 	newCommands.register("feeds", handlerGetFeeds)
+	newCommands.register("feed", middlewareLoggedIn(handlerFeed))
 	newCommands.register("follow", middlewareLoggedIn(handlerFollow))
 	newCommands.register("following", middlewareLoggedIn(handlerFollowing))
 	newCommands.register("unfollow", middlewareLoggedIn(handlerUnfollow))
+	newCommands.register("folder", middlewareLoggedIn(handlerFolder))
+	newCommands.register("filter", middlewareLoggedIn(handlerFilter))
+	newCommands.register("webhook", middlewareLoggedIn(handlerWebhook))
+	newCommands.register("digest", middlewareLoggedIn(handlerDigest))
 	newCommands.register("browse", middlewareLoggedIn(handlerBrowseFeeds))
+	newCommands.register("categories", middlewareLoggedIn(handlerCategories))
+	newCommands.register("episodes", middlewareLoggedIn(handlerEpisodes))
+	newCommands.register("download", middlewareLoggedIn(handlerDownload))
+	newCommands.register("tui", middlewareLoggedIn(handlerTUI))
+	newCommands.register("config", handlerConfig)
+	newCommands.register("migrate", handlerMigrate)
+	newCommands.register("backup", handlerBackup)
+	newCommands.register("restore", handlerRestore)
+	newCommands.register("admin", middlewareLoggedIn(handlerAdmin))
 
 	return &newCommands
 }
 
-func NewState(cfg *config.Config, db *database.Queries) *state {
+// NewState returns the state the handlers run with. conn is the SQL database
+// behind store, or nil when store is not backed by one, e.g. a
+// storage.Memory; the commands in sqlCommands are then unavailable.
+func NewState(cfg *config.Config, store storage.Store, conn *storage.DB) *state {
 	newState := state{
-		db:  db,
-		cfg: cfg,
+		store: store,
+		conn:  conn,
+		cfg:   cfg,
+	}
+	if conn != nil {
+		newState.db = conn.Queries()
 	}
 
 	return &newState
@@ -69,13 +161,33 @@ func NewState(cfg *config.Config, db *database.Queries) *state {
 func handlerFollowing(s *state, cmd command, user database.User) error {
 	ctx := context.Background()
 
-	userFeeds, err := s.db.GetFeedFollowsForUser(ctx, user.Name)
+	userFeeds, err := s.store.GetFeedFollowsForUser(ctx, user.Name)
 	if err != nil {
 		return err
 	}
 
+	var folders []database.Folder
+	if s.db != nil {
+		folders, err = s.db.GetFoldersForUser(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	folderFeeds := make(map[string][]string)
 	for _, feed := range userFeeds {
-		fmt.Printf("%s\n", feed.FeedName)
+		if !feed.FolderName.Valid {
+			fmt.Printf("%s\n", feed.FeedName)
+			continue
+		}
+		folderFeeds[feed.FolderName.String] = append(folderFeeds[feed.FolderName.String], feed.FeedName)
+	}
+
+	for _, folder := range folders {
+		fmt.Printf("%s/\n", folder.Name)
+		for _, feedName := range folderFeeds[folder.Name] {
+			fmt.Printf("  %s\n", feedName)
+		}
 	}
 
 	return nil
@@ -92,7 +204,7 @@ func handlerFollow(s *state, cmd command, user database.User) error {
 
 	current_time := time.Now()
 
-	feed, err := s.db.GetFeedByUrl(ctx, url)
+	feed, err := s.store.GetFeedByUrl(ctx, url)
 	if err != nil {
 		return err
 	}
@@ -105,7 +217,7 @@ func handlerFollow(s *state, cmd command, user database.User) error {
 		FeedID:    feed.ID,
 	}
 
-	feedFollow, err := s.db.CreateFeedFollow(ctx, args)
+	feedFollow, err := s.store.CreateFeedFollow(ctx, args)
 	if err != nil {
 		return err
 	}
@@ -116,13 +228,27 @@ func handlerFollow(s *state, cmd command, user database.User) error {
 
 func handlerGetFeeds(s *state, cmd command) error {
 	ctx := context.Background()
-	feedData, err := s.db.GetFeeds(ctx)
+	feedData, err := s.store.GetFeeds(ctx)
 	if err != nil {
 		return err
 	}
 
 	for _, feed := range feedData {
-		fmt.Printf("%+v\n", feed)
+		podcastTag := ""
+		if feed.IsPodcast {
+			podcastTag = " [podcast]"
+		}
+		addedBy := "unknown"
+		if feed.AddedByName.Valid {
+			addedBy = feed.AddedByName.String
+		}
+		status := "never fetched"
+		if feed.LastFetchStatus.Valid {
+			status = feed.LastFetchStatus.String
+		} else if feed.LastFetchedAt.Valid {
+			status = "fetched"
+		}
+		fmt.Printf("* %s (%s) added by %s, %d followers, %s%s\n", feed.FeedName, feed.Url, addedBy, feed.Followers, status, podcastTag)
 	}
 
 	return nil
@@ -140,7 +266,7 @@ func handlerUnfollow(s *state, cmd command, user database.User) error {
 		UserID: user.ID,
 		Url:    feedUrl,
 	}
-	err := s.db.DeleteFeedFollowByUrl(ctx, deleteFeedFollowByUrlParams)
+	err := s.store.DeleteFeedFollowByUrl(ctx, deleteFeedFollowByUrlParams)
 	if err != nil {
 		return err
 	}
@@ -165,9 +291,10 @@ func handlerAddFeed(s *state, cmd command, user database.User) error {
 		UpdatedAt: currentTime,
 		Name:      feedName,
 		Url:       feedUrl,
+		AddedBy:   uuid.NullUUID{UUID: user.ID, Valid: true},
 	}
 
-	feed, err := s.db.CreateFeed(ctx, feedParams)
+	feed, err := s.store.CreateFeed(ctx, feedParams)
 	if err != nil {
 		return err
 	}
@@ -179,7 +306,7 @@ func handlerAddFeed(s *state, cmd command, user database.User) error {
 		UserID:    user.ID,
 		FeedID:    feed.ID,
 	}
-	_, err = s.db.CreateFeedFollow(ctx, feedFollowParams)
+	_, err = s.store.CreateFeedFollow(ctx, feedFollowParams)
 	if err != nil {
 		return err
 	}
@@ -191,23 +318,47 @@ func handlerAddFeed(s *state, cmd command, user database.User) error {
 func handlerAggregation(s *state, cmd command) error {
 	ctx := context.Background()
 
-	if len(cmd.args) < 0 {
-		return fmt.Errorf("must provide a scrape duration")
+	// Feeds are fetched as they become due; the optional argument spaces
+	// out requests when several feeds are due at once.
+	var timeBetweenRequests time.Duration
+	if len(cmd.args) > 0 {
+		var err error
+		timeBetweenRequests, err = time.ParseDuration(cmd.args[0])
+		if err != nil {
+			return err
+		}
 	}
 
-	timeBetweenRequests, err := time.ParseDuration(cmd.args[0])
+	_, _, err := s.cfg.FetchIntervals()
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Collecting feeds every %s\n", timeBetweenRequests)
+	fetcher, err := newFetcher(s.cfg)
+	if err != nil {
+		return err
+	}
+
+	webhooks := newWebhookSender(webhookWorkers)
+	defer webhooks.Wait()
+
+	fmt.Printf("Collecting feeds as they become due, at most one every %s\n", timeBetweenRequests)
+
+	for {
+		err = scrapeFeeds(ctx, s, fetcher, webhooks)
+		if err != nil {
+			return err
+		}
 
-	ticker := time.NewTicker(timeBetweenRequests)
-	for ; ; <-ticker.C {
-		err = scrapeFeeds(ctx, s)
+		wait, err := untilNextFetch(ctx, s)
 		if err != nil {
 			return err
 		}
+		if wait > timeBetweenRequests {
+			fmt.Printf("no feed is due, sleeping until %s\n", time.Now().Add(wait).Format(time.DateTime))
+		}
+
+		time.Sleep(max(wait, timeBetweenRequests))
 	}
 }
 
@@ -218,7 +369,7 @@ func handlerLogin(s *state, cmd command) error {
 
 	ctx := context.Background()
 
-	user, err := s.db.GetUser(ctx, cmd.args[0])
+	user, err := s.store.GetUser(ctx, cmd.args[0])
 	if err != nil {
 		return err
 	}
@@ -240,15 +391,27 @@ func handlerRegister(s *state, cmd command) error {
 
 	ctx := context.Background()
 
+	// The first user, or the first after every admin was deleted, administers
+	// gator.
+	role := roleMember
+	admins, err := s.store.CountUsersWithRole(ctx, roleAdmin)
+	if err != nil {
+		return err
+	}
+	if admins == 0 {
+		role = roleAdmin
+	}
+
 	currentTime := time.Now()
 	args := database.CreateUserParams{
 		ID:        uuid.New(),
 		CreatedAt: currentTime,
 		UpdatedAt: currentTime,
 		Name:      cmd.args[0],
+		Role:      role,
 	}
 
-	user, err := s.db.CreateUser(ctx, args)
+	user, err := s.store.CreateUser(ctx, args)
 
 	if err != nil {
 		return err
@@ -265,70 +428,98 @@ func handlerRegister(s *state, cmd command) error {
 	return nil
 }
 
-func handlerReset(s *state, cmd command) error {
+func handlerGetUsers(s *state, cmd command) error {
 	ctx := context.Background()
 
-	err := s.db.DeleteUser(ctx)
+	users, err := s.store.GetUsers(ctx)
 
 	if err != nil {
 		return err
 	}
 
-	err = s.db.DeleteFeeds(ctx)
-	if err != nil {
-		return err
+	for _, user := range users {
+		tags := ""
+		if user.Role == roleAdmin {
+			tags += " (admin)"
+		}
+		if user.Name == s.cfg.CurrentUserName {
+			tags += " (current)"
+		}
+		fmt.Printf("* %s%s\n", user.Name, tags)
 	}
 
-	fmt.Println("Deletion successful")
 	return nil
 }
 
-func handlerGetUsers(s *state, cmd command) error {
+func handlerBrowseFeeds(s *state, cmd command, user database.User) error {
 	ctx := context.Background()
 
-	users, err := s.db.GetUsers(ctx)
-
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	category := fs.String("category", "", "only show posts in this category")
+	author := fs.String("author", "", "only show posts by this author")
+	folder := fs.String("folder", "", "only show posts of feeds in this folder")
+	feedUrl := fs.String("feed", "", "only show posts of the feed with this url")
+	args, err := parseFlags(fs, cmd.args)
 	if err != nil {
 		return err
 	}
 
-	for _, user := range users {
-		if user.Name == s.cfg.CurrentUserName {
-			fmt.Printf("* %s (current)\n", user.Name)
-		} else {
-			fmt.Printf("* %s\n", user.Name)
+	postLimit := 2
+	if len(args) > 0 {
+		limit, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
 		}
+
+		postLimit = limit
+	}
+
+	getPostsForUserParams := database.GetPostsForUserParams{
+		ID:         user.ID,
+		Folder:     *folder,
+		FeedUrl:    *feedUrl,
+		Author:     *author,
+		Category:   *category,
+		LimitCount: int32(postLimit),
+	}
+	results, err := s.store.GetPostsForUser(ctx, getPostsForUserParams)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("browsing!")
+	fmt.Println(len(results))
+	for _, result := range results {
+		fmt.Printf("%+v\n", result)
 	}
 
 	return nil
 }
 
-func handlerBrowseFeeds(s *state, cmd command, user database.User) error {
+func handlerCategories(s *state, cmd command, user database.User) error {
 	ctx := context.Background()
 
-	postLimit := 2
+	categoryLimit := 20
 	if len(cmd.args) > 0 {
 		limit, err := strconv.Atoi(cmd.args[0])
 		if err != nil {
 			return err
 		}
 
-		postLimit = limit
+		categoryLimit = limit
 	}
 
-	getPostsForUserParams := database.GetPostsForUserParams{
-		ID:    user.ID,
-		Limit: int32(postLimit),
+	getTopCategoriesParams := database.GetTopCategoriesForUserParams{
+		UserID: user.ID,
+		Limit:  int32(categoryLimit),
 	}
-	results, err := s.db.GetPostsForUser(ctx, getPostsForUserParams)
+	categories, err := s.store.GetTopCategoriesForUser(ctx, getTopCategoriesParams)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println("browsing!")
-	fmt.Println(len(results))
-	for _, result := range results {
-		fmt.Printf("%+v\n", result)
+	for _, category := range categories {
+		fmt.Printf("* %s (%d)\n", category.Name, category.PostCount)
 	}
 
 	return nil
@@ -345,7 +536,27 @@ func (c *commands) Run(s *state, cmd command) error {
 		return fmt.Errorf("command %s not found", cmd.name)
 	}
 
-	err := commandFunc(s, cmd)
+	if !s.cfg.ProfileExists() && cmd.name != "config" {
+		return fmt.Errorf("profile %q not found in %s, create it with: gator --profile %s config set db_url <url>", s.cfg.Profile(), s.cfg.Path(), s.cfg.Profile())
+	}
+
+	if !schemaExempt[cmd.name] && s.conn != nil {
+		err := migrate.Check(context.Background(), s.conn)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := checkPermission(s, cmd)
+	if err != nil {
+		return err
+	}
+
+	if sqlCommands[cmd.name] && s.conn == nil {
+		return fmt.Errorf("%s: %w", cmd.name, storage.ErrNotSupported)
+	}
+
+	err = commandFunc(s, cmd)
 	if err != nil {
 		return err
 	}
@@ -357,7 +568,7 @@ func middlewareLoggedIn(handler func(s *state, cmd command, user database.User)
 	ctx := context.Background()
 
 	return func(s *state, cmd command) error {
-		user, err := s.db.GetUser(ctx, s.cfg.CurrentUserName)
+		user, err := s.store.GetUser(ctx, s.cfg.CurrentUserName)
 		if err != nil {
 			return err
 		}
@@ -366,30 +577,146 @@ func middlewareLoggedIn(handler func(s *state, cmd command, user database.User)
 	}
 }
 
-func scrapeFeeds(ctx context.Context, s *state) error {
-	fmt.Println("visiting next feed...\n")
-	feed, err := s.db.GetNextFeedToFetch(ctx)
+// checkPermission makes sure the current user has the role commandRoles
+// requires for cmd.
+func checkPermission(s *state, cmd command) error {
+	role, ok := commandRoles[cmd.name]
+	if !ok {
+		return nil
+	}
+
+	if setupCommands[cmd.name] {
+		setup, err := beforeFirstUser(context.Background(), s)
+		if err != nil {
+			return err
+		}
+		if setup {
+			return nil
+		}
+	}
+
+	user, err := s.store.GetUser(context.Background(), s.cfg.CurrentUserName)
 	if err != nil {
-		return err
+		return fmt.Errorf("%s requires a logged in %s, use login or register", cmd.name, role)
+	}
+
+	return requireRole(user, role, cmd.name)
+}
+
+// beforeFirstUser reports whether nobody could log in as an admin yet,
+// because the schema predates user roles, as on a fresh install, or no user
+// has registered.
+func beforeFirstUser(ctx context.Context, s *state) (bool, error) {
+	if s.conn != nil {
+		version, err := migrate.Version(ctx, s.conn)
+		if err != nil {
+			return false, err
+		}
+		if version < userRolesVersion {
+			return true, nil
+		}
+	}
+
+	users, err := s.store.GetUsers(ctx)
+	if err != nil {
+		return false, err
 	}
 
-	markFeedFetchedResult, err := s.db.MarkFeedFetched(ctx, feed.ID)
+	return len(users) == 0, nil
+}
+
+// requireRole returns an error unless user has role, naming action as what
+// needs it.
+func requireRole(user database.User, role, action string) error {
+	if user.Role != role {
+		return fmt.Errorf("%s requires the %s role, which %s does not have", action, role, user.Name)
+	}
+
+	return nil
+}
+
+// scrapeFeeds fetches the next due feed with fetcher, checking robots.txt
+// first when the fetcher has a Robots. Webhook notifications about its new
+// posts are left running on webhooks.
+func scrapeFeeds(ctx context.Context, s *state, fetcher *rss.Fetcher, webhooks *webhookSender) error {
+	fmt.Println("visiting next feed...")
+	fetchedAt := time.Now()
+	feed, err := s.store.GetNextFeedToFetch(ctx, sql.NullTime{Time: fetchedAt, Valid: true})
+	if errors.Is(err, sql.ErrNoRows) {
+		fmt.Println("no feed is due yet")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
-	feedResults, err := rss.FetchFeed(ctx, markFeedFetchedResult.Url)
+	allowed := true
+	var robotsErr error
+	if fetcher.Robots != nil {
+		var crawlDelay time.Duration
+		allowed, crawlDelay, robotsErr = fetcher.Robots.Check(ctx, feed.Url)
+		if robotsErr == nil {
+			fetcher.SetCrawlDelay(feed.Url, crawlDelay)
+		}
+	}
+
+	// Rather than wait for a busy host, agg moves on to feeds on other hosts
+	// and comes back once the host allows another request.
+	if delay := fetcher.Delay(feed.Url); delay > 0 {
+		fmt.Printf("postponing %s for %s, its host was just visited\n", feed.Url, delay.Round(100*time.Millisecond))
+		postponeFeedFetchParams := database.PostponeFeedFetchParams{
+			ID: feed.ID,
+			NextFetchAt: sql.NullTime{
+				Time:  fetchedAt.Add(delay),
+				Valid: true,
+			},
+		}
+		return s.store.PostponeFeedFetch(ctx, postponeFeedFetchParams)
+	}
+
+	markFeedFetchedResult, err := s.store.MarkFeedFetched(ctx, feed.ID)
 	if err != nil {
 		return err
 	}
 
-	for _, feedResult := range feedResults.Channel.Item {
+	if robotsErr != nil || !allowed {
+		status, message := fetchStatusError, ""
+		if robotsErr != nil {
+			message = robotsErr.Error()
+		} else {
+			status, message = fetchStatusBlocked, "disallowed by robots.txt"
+		}
+		fmt.Printf("couldn't fetch %s: %s\n", markFeedFetchedResult.Url, message)
 
-		currentTime := time.Now()
-		parsedPubTime, err := parsePubTime(feedResult.PubDate)
+		interval, err := pollInterval(ctx, s, markFeedFetchedResult)
 		if err != nil {
 			return err
 		}
+		return recordFeedFetch(ctx, s, markFeedFetchedResult.ID, status, message, 0, fetchedAt.Add(interval))
+	}
+
+	feedResults, err := fetcher.FetchFeed(ctx, markFeedFetchedResult.Url)
+	if err != nil {
+		// A broken feed is reported by feed show and retried on its usual
+		// schedule instead of stopping agg, or once the server allows it when
+		// it asked us to back off for longer.
+		fmt.Printf("couldn't fetch %s: %v\n", markFeedFetchedResult.Url, err)
+		interval, intervalErr := pollInterval(ctx, s, markFeedFetchedResult)
+		if intervalErr != nil {
+			return intervalErr
+		}
+		var statusErr *rss.StatusError
+		if errors.As(err, &statusErr) {
+			interval = max(interval, min(statusErr.RetryAfter, rss.MaxRefreshInterval))
+		}
+		return recordFeedFetch(ctx, s, markFeedFetchedResult.ID, fetchStatusError, err.Error(), 0, fetchedAt.Add(interval))
+	}
+
+	var newPosts []database.Post
+	var downloads []pendingDownload
+	for _, feedResult := range feedResults.Channel.Item {
+
+		currentTime := time.Now()
 		savePostParams := database.CreatePostParams{
 			ID:        uuid.New(),
 			CreatedAt: currentTime,
@@ -400,20 +727,91 @@ func scrapeFeeds(ctx context.Context, s *state) error {
 				String: feedResult.Description,
 				Valid:  true,
 			},
-			PublishedAt: sql.NullTime{
-				Time:  parsedPubTime,
-				Valid: true,
+			PublishedAt: publishedTime(feedResult, currentTime),
+			FeedID:      markFeedFetchedResult.ID,
+			Author: sql.NullString{
+				String: feedResult.AuthorName(),
+				Valid:  feedResult.AuthorName() != "",
 			},
-			FeedID: markFeedFetchedResult.ID,
 		}
-		_, err = s.db.CreatePost(ctx, savePostParams)
+		post, err := s.store.CreatePost(ctx, savePostParams)
+		if err != nil {
+			if storage.IsDuplicate(err) {
+				continue
+			}
+			return err
+		}
+		newPosts = append(newPosts, post)
+
+		for _, category := range feedResult.CategoryNames() {
+			createPostCategoryParams := database.CreatePostCategoryParams{
+				PostID: post.ID,
+				Name:   category,
+			}
+			err = s.store.CreatePostCategory(ctx, createPostCategoryParams)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Enclosures, webhooks and downloads are kept outside the Store.
+		if s.db == nil {
+			continue
+		}
+
+		enclosures, err := saveEnclosures(ctx, s, post, feedResult)
 		if err != nil {
-			pgErr, ok := err.(*pq.Error)
-			if ok {
-				if pgErr.Code == "23505" && pgErr.Code.Name() == "unique_violation" {
-					continue
+			return err
+		}
+
+		if markFeedFetchedResult.AutoDownload {
+			for _, enclosure := range enclosures {
+				if (rss.Enclosure{MIMEType: enclosure.MimeType}).IsAudioVisual() {
+					downloads = append(downloads, newPendingDownload(markFeedFetchedResult.Name, post.Title, post.PublishedAt, enclosure))
 				}
 			}
+		}
+	}
+
+	isPodcast := feedResults.IsPodcast()
+	if isPodcast != markFeedFetchedResult.IsPodcast {
+		setFeedIsPodcastParams := database.SetFeedIsPodcastParams{
+			ID:        markFeedFetchedResult.ID,
+			IsPodcast: isPodcast,
+		}
+		err = s.store.SetFeedIsPodcast(ctx, setFeedIsPodcastParams)
+		if err != nil {
+			return err
+		}
+	}
+
+	interval, err := pollInterval(ctx, s, markFeedFetchedResult)
+	if err != nil {
+		return err
+	}
+
+	err = recordFeedFetch(ctx, s, markFeedFetchedResult.ID, fetchStatusOK, "", len(newPosts), feedResults.NextFetch(fetchedAt, interval))
+	if err != nil {
+		return err
+	}
+
+	if s.db == nil {
+		return nil
+	}
+
+	err = notifyWebhooks(ctx, s, webhooks, markFeedFetchedResult, newPosts)
+	if err != nil {
+		return err
+	}
+
+	if len(downloads) > 0 {
+		err = downloadEnclosures(ctx, s, downloads, s.cfg.DownloadWorkers())
+		if err != nil {
+			return err
+		}
+
+		err = enforceDownloadQuota(ctx, s)
+		if err != nil {
 			return err
 		}
 	}
@@ -421,32 +819,87 @@ func scrapeFeeds(ctx context.Context, s *state) error {
 	return nil
 }
 
-var timeFormats = []string{
-	time.RFC1123Z,
-	time.RFC3339Nano,
-	time.RFC3339,
-	time.RFC1123Z,
-	time.RFC1123,
-	time.RFC850,
-	time.RFC822Z,
-	time.RFC822,
-	time.RubyDate,
-	time.UnixDate,
-	time.ANSIC,
-	time.Layout,
+// recordFeedFetch stores the result of fetching a feed. agg skips the feed
+// until nextFetchAt; a zero or past time leaves it due on its next turn.
+func recordFeedFetch(ctx context.Context, s *state, feedID uuid.UUID, status, message string, newPosts int, nextFetchAt time.Time) error {
+	recordFeedFetchParams := database.RecordFeedFetchParams{
+		ID: feedID,
+		LastFetchStatus: sql.NullString{
+			String: status,
+			Valid:  true,
+		},
+		LastFetchError: sql.NullString{
+			String: message,
+			Valid:  message != "",
+		},
+		LastFetchNewPosts: sql.NullInt32{
+			Int32: int32(newPosts),
+			Valid: status == fetchStatusOK,
+		},
+		NextFetchAt: sql.NullTime{
+			Time:  nextFetchAt,
+			Valid: nextFetchAt.After(time.Now()),
+		},
+	}
+	return s.store.RecordFeedFetch(ctx, recordFeedFetchParams)
 }
 
-func parsePubTime(timeStr string) (time.Time, error) {
-	var err error
-	for _, format := range timeFormats {
-		parsedTime, err := time.Parse(format, timeStr)
+func saveEnclosures(ctx context.Context, s *state, post database.Post, item rss.RSSItem) ([]database.Enclosure, error) {
+	var enclosures []database.Enclosure
+	for _, enclosure := range item.Media() {
+		currentTime := time.Now()
+		createEnclosureParams := database.CreateEnclosureParams{
+			ID:        uuid.New(),
+			CreatedAt: currentTime,
+			UpdatedAt: currentTime,
+			PostID:    post.ID,
+			Url:       enclosure.URL,
+			MimeType:  enclosure.MIMEType,
+			Length: sql.NullInt64{
+				Int64: enclosure.Length,
+				Valid: enclosure.Length > 0,
+			},
+			DurationSeconds: sql.NullInt32{
+				Int32: int32(enclosure.Duration / time.Second),
+				Valid: enclosure.Duration > 0,
+			},
+			ImageUrl: sql.NullString{
+				String: enclosure.ImageURL,
+				Valid:  enclosure.ImageURL != "",
+			},
+		}
+		savedEnclosure, err := s.db.CreateEnclosure(ctx, createEnclosureParams)
 		if err != nil {
-			continue
+			return nil, err
 		}
 
-		return parsedTime, nil
+		enclosures = append(enclosures, savedEnclosure)
 	}
 
-	return time.Time{}, err
+	return enclosures, nil
+}
 
+// publishedTime resolves the publication time of a feed item. Items without a
+// date are treated as published when first seen, which must be the post's
+// created_at so that pollInterval can tell them apart, and dates that cannot
+// be parsed are stored as NULL instead of aborting the scrape.
+func publishedTime(item rss.RSSItem, firstSeen time.Time) sql.NullTime {
+	rawDate := item.PublishedDate()
+	if strings.TrimSpace(rawDate) == "" {
+		return sql.NullTime{
+			Time:  firstSeen,
+			Valid: true,
+		}
+	}
+
+	parsedTime, err := rss.ParseDate(rawDate)
+	if err != nil {
+		fmt.Printf("could not parse publication date of %s: %v\n", item.Link, err)
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{
+		Time:  parsedTime,
+		Valid: true,
+	}
 }
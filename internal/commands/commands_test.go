@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ctiller15/gator/internal/rss"
+)
+
+func TestPublishedTime(t *testing.T) {
+	firstSeen := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		item      rss.RSSItem
+		wantValid bool
+		want      time.Time
+	}{
+		{"pubDate", rss.RSSItem{PubDate: "Wed, 4 Sep 2024 09:30:00 EST"}, true, time.Date(2024, 9, 4, 14, 30, 0, 0, time.UTC)},
+		{"dc:date", rss.RSSItem{DCDate: "2024-03-10T12:30:00"}, true, time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)},
+		{"undated falls back to first seen", rss.RSSItem{}, true, firstSeen},
+		{"blank falls back to first seen", rss.RSSItem{PubDate: "  "}, true, firstSeen},
+		{"garbage is NULL", rss.RSSItem{PubDate: "sometime last week"}, false, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := publishedTime(tt.item, firstSeen)
+			if got.Valid != tt.wantValid {
+				t.Fatalf("publishedTime() valid = %v, want %v", got.Valid, tt.wantValid)
+			}
+			if tt.wantValid && !got.Time.Equal(tt.want) {
+				t.Errorf("publishedTime() = %s, want %s", got.Time.UTC(), tt.want)
+			}
+		})
+	}
+}
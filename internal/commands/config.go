@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/ctiller15/gator/internal/config"
+)
+
+func handlerConfig(s *state, cmd command) error {
+	if len(cmd.args) == 0 {
+		return fmt.Errorf("usage: config get|set|show")
+	}
+
+	args := cmd.args[1:]
+	switch cmd.args[0] {
+	case "get":
+		return handlerConfigGet(s, args)
+	case "set":
+		return handlerConfigSet(s, args)
+	case "show":
+		return handlerConfigShow(s)
+	default:
+		return fmt.Errorf("unknown config command %s", cmd.args[0])
+	}
+}
+
+func handlerConfigGet(s *state, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: config get <setting>")
+	}
+
+	value, err := s.cfg.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
+func handlerConfigSet(s *state, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: config set <setting> <value>")
+	}
+
+	name, value := args[0], args[1]
+	err := s.cfg.Set(name, value)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("set %s in %s\n", name, configLocation(s.cfg))
+	if _, ok := os.LookupEnv(config.EnvironmentVariable(name)); ok {
+		fmt.Printf("note: %s is set and overrides this value\n", config.EnvironmentVariable(name))
+	}
+
+	return nil
+}
+
+func handlerConfigShow(s *state) error {
+	fmt.Printf("# %s\n", configLocation(s.cfg))
+
+	for _, name := range config.Settings() {
+		value, err := s.cfg.Get(name)
+		if err != nil {
+			return err
+		}
+
+		source := ""
+		if _, ok := os.LookupEnv(config.EnvironmentVariable(name)); ok {
+			source = fmt.Sprintf(" (from %s)", config.EnvironmentVariable(name))
+		}
+		fmt.Printf("%s = %s%s\n", name, redactSetting(name, value), source)
+	}
+
+	return nil
+}
+
+func configLocation(cfg *config.Config) string {
+	if cfg.Profile() == "" {
+		return cfg.Path()
+	}
+
+	return fmt.Sprintf("%s (profile %s)", cfg.Path(), cfg.Profile())
+}
+
+// redactSetting hides passwords when printing settings.
+func redactSetting(name, value string) string {
+	switch name {
+	case "smtp_password":
+		if value != "" {
+			return "********"
+		}
+	case "db_url":
+		parsed, err := url.Parse(value)
+		if err == nil {
+			return parsed.Redacted()
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,319 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/ctiller15/gator/internal/digest"
+)
+
+// defaultDigestWindow is how far back the first digest of a user reaches.
+const defaultDigestWindow = 24 * time.Hour
+
+func handlerDigest(s *state, cmd command, user database.User) error {
+	if len(cmd.args) > 0 && cmd.args[0] == "email" {
+		return handlerDigestEmail(s, cmd.args[1:], user)
+	}
+
+	ctx := context.Background()
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	allUsers := fs.Bool("all", false, "send a digest to every user with an email address")
+	since := fs.Duration("since", 0, "include posts collected within this duration instead of since the last digest")
+	dryRun := fs.Bool("dry-run", false, "write the email to a .eml file instead of sending it")
+	outDir := fs.String("out", ".", "directory for files written by --dry-run and --format")
+	format := fs.String("format", "", "export a page as md or html instead of sending an email")
+	from := fs.String("from", "", "first publication date (YYYY-MM-DD) of an exported page")
+	to := fs.String("to", "", "last publication date (YYYY-MM-DD) of an exported page")
+	templatePath := fs.String("template", "", "template file to render an exported page with")
+	_, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return err
+	}
+
+	if *format != "" {
+		return exportDigest(ctx, s, user, *format, *from, *to, *templatePath, *outDir)
+	}
+
+	recipients := []database.User{user}
+	if *allUsers {
+		err = requireRole(user, roleAdmin, "digest --all")
+		if err != nil {
+			return err
+		}
+
+		recipients, err = s.db.GetUsersWithEmail(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, recipient := range recipients {
+		err = sendDigest(ctx, s, recipient, *since, *dryRun, *outDir)
+		if err != nil {
+			return fmt.Errorf("digest for %s: %w", recipient.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func handlerDigestEmail(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	if len(args) == 0 {
+		return fmt.Errorf("must provide an email address")
+	}
+
+	address, err := mail.ParseAddress(args[0])
+	if err != nil {
+		return err
+	}
+
+	setUserEmailParams := database.SetUserEmailParams{
+		ID: user.ID,
+		Email: sql.NullString{
+			String: address.Address,
+			Valid:  true,
+		},
+	}
+	err = s.db.SetUserEmail(ctx, setUserEmailParams)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("digests for %s will be sent to %s\n", user.Name, address.Address)
+	return nil
+}
+
+func sendDigest(ctx context.Context, s *state, user database.User, window time.Duration, dryRun bool, outDir string) error {
+	until := time.Now()
+	since := until.Add(-defaultDigestWindow)
+	switch {
+	case window > 0:
+		since = until.Add(-window)
+	case user.LastDigestAt.Valid:
+		since = user.LastDigestAt.Time
+	}
+
+	getDigestPostsParams := database.GetDigestPostsForUserParams{
+		UserID: user.ID,
+		Since:  since,
+		Until:  until,
+	}
+	posts, err := s.db.GetDigestPostsForUser(ctx, getDigestPostsParams)
+	if err != nil {
+		return err
+	}
+
+	if len(posts) == 0 {
+		fmt.Printf("no new posts for %s\n", user.Name)
+		return nil
+	}
+
+	userDigest := digest.New(user.Name, since, until, digestItems(posts))
+	renderer := digest.Renderer{Dir: s.cfg.DigestTemplateDir}
+
+	text, err := renderer.Render(digest.EmailTextTemplate, userDigest)
+	if err != nil {
+		return err
+	}
+
+	html, err := renderer.Render(digest.EmailHTMLTemplate, userDigest)
+	if err != nil {
+		return err
+	}
+
+	recipient := user.Email.String
+	if !user.Email.Valid {
+		if !dryRun {
+			return fmt.Errorf("no email address set, use digest email <address>")
+		}
+		recipient = user.Name + "@localhost"
+	}
+
+	sender := s.cfg.SMTPFrom
+	if sender == "" {
+		sender = "gator@localhost"
+	}
+
+	message := digest.Message{
+		From:    sender,
+		To:      recipient,
+		Subject: fmt.Sprintf("gator digest: %d new post(s)", len(posts)),
+		Date:    until,
+		Text:    text,
+		HTML:    html,
+	}
+
+	if dryRun {
+		data, err := message.Bytes()
+		if err != nil {
+			return err
+		}
+
+		err = os.MkdirAll(outDir, 0755)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(outDir, fmt.Sprintf("%s-%s.eml", slugify(user.Name), until.Format("20060102-150405")))
+		err = os.WriteFile(path, data, 0644)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("wrote digest for %s to %s\n", user.Name, path)
+		return nil
+	}
+
+	if s.cfg.SMTPHost == "" {
+		return fmt.Errorf("smtp_host is not configured")
+	}
+
+	smtpConfig := digest.SMTPConfig{
+		Host:     s.cfg.SMTPHost,
+		Port:     s.cfg.SMTPServerPort(),
+		Username: s.cfg.SMTPUsername,
+		Password: s.cfg.SMTPPassword,
+	}
+	err = digest.Send(smtpConfig, message)
+	if err != nil {
+		return err
+	}
+
+	markUserDigestedParams := database.MarkUserDigestedParams{
+		ID: user.ID,
+		LastDigestAt: sql.NullTime{
+			Time:  until,
+			Valid: true,
+		},
+	}
+	err = s.db.MarkUserDigested(ctx, markUserDigestedParams)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("sent digest of %d post(s) to %s\n", len(posts), recipient)
+	return nil
+}
+
+// exportDigest renders the posts published in a date range as a standalone
+// Markdown or HTML page.
+func exportDigest(ctx context.Context, s *state, user database.User, format, from, to, templatePath, outDir string) error {
+	templateName := map[string]string{
+		"md":   digest.PageMarkdownTemplate,
+		"html": digest.PageHTMLTemplate,
+	}[format]
+	if templateName == "" {
+		return fmt.Errorf("format must be md or html")
+	}
+
+	firstDay, lastDay, err := digestRange(from, to, time.Now())
+	if err != nil {
+		return err
+	}
+
+	getPostsParams := database.GetPostsPublishedForUserParams{
+		UserID: user.ID,
+		PublishedFrom: sql.NullTime{
+			Time:  firstDay,
+			Valid: true,
+		},
+		PublishedTo: sql.NullTime{
+			Time:  lastDay.AddDate(0, 0, 1),
+			Valid: true,
+		},
+	}
+	posts, err := s.db.GetPostsPublishedForUser(ctx, getPostsParams)
+	if err != nil {
+		return err
+	}
+
+	var rows []database.GetDigestPostsForUserRow
+	for _, post := range posts {
+		rows = append(rows, database.GetDigestPostsForUserRow(post))
+	}
+	userDigest := digest.New(user.Name, firstDay, lastDay, digestItems(rows))
+
+	var page string
+	if templatePath != "" {
+		page, err = digest.RenderFile(templatePath, userDigest)
+	} else {
+		renderer := digest.Renderer{Dir: s.cfg.DigestTemplateDir}
+		page, err = renderer.Render(templateName, userDigest)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(outDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(outDir, fmt.Sprintf("%s-%s-%s.%s", slugify(user.Name), firstDay.Format(time.DateOnly), lastDay.Format(time.DateOnly), format))
+	err = os.WriteFile(path, []byte(page), 0644)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote %d post(s) to %s\n", len(posts), path)
+	return nil
+}
+
+// digestRange returns the first and last day of an export, given as UTC
+// midnights like the --from and --to dates parsed into them. By default it
+// covers the week up to the local date of now.
+func digestRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	year, month, day := now.Date()
+	lastDay := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if to != "" {
+		parsed, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --to date: %w", err)
+		}
+		lastDay = parsed
+	}
+
+	firstDay := lastDay.AddDate(0, 0, -6)
+	if from != "" {
+		parsed, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --from date: %w", err)
+		}
+		firstDay = parsed
+	}
+
+	if lastDay.Before(firstDay) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--to must not be before --from")
+	}
+
+	return firstDay, lastDay, nil
+}
+
+func digestItems(posts []database.GetDigestPostsForUserRow) []digest.Item {
+	var items []digest.Item
+	for _, post := range posts {
+		items = append(items, digest.Item{
+			FeedName: post.FeedName,
+			FeedURL:  post.FeedUrl,
+			Post: digest.Post{
+				Title:       post.Title,
+				URL:         post.Url,
+				Description: post.Description.String,
+				Author:      post.Author.String,
+				PublishedAt: post.PublishedAt.Time,
+			},
+		})
+	}
+
+	return items
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDigestRange(t *testing.T) {
+	// 08:00 on 10 May in Tokyo is still 9 May in UTC.
+	tokyo := time.FixedZone("JST", 9*60*60)
+	morning := time.Date(2024, 5, 10, 8, 0, 0, 0, tokyo)
+
+	date := func(value string) time.Time {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name      string
+		from, to  string
+		wantFirst string
+		wantLast  string
+		wantErr   bool
+	}{
+		{"defaults to the local week", "", "", "2024-05-04", "2024-05-10", false},
+		{"to", "", "2024-04-30", "2024-04-24", "2024-04-30", false},
+		{"from", "2024-05-08", "", "2024-05-08", "2024-05-10", false},
+		{"both", "2024-01-01", "2024-01-31", "2024-01-01", "2024-01-31", false},
+		{"single day", "2024-01-01", "2024-01-01", "2024-01-01", "2024-01-01", false},
+		{"reversed", "2024-02-01", "2024-01-01", "", "", true},
+		{"invalid to", "", "10/05/2024", "", "", true},
+		{"invalid from", "yesterday", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last, err := digestRange(tt.from, tt.to, morning)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("digestRange() error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !first.Equal(date(tt.wantFirst)) || !last.Equal(date(tt.wantLast)) {
+				t.Errorf("digestRange() = %s to %s, want %s to %s", first.Format(time.DateOnly), last.Format(time.DateOnly), tt.wantFirst, tt.wantLast)
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/ctiller15/gator/internal/download"
+	"github.com/google/uuid"
+)
+
+// pendingDownload ties a download job to the enclosure it was created for.
+type pendingDownload struct {
+	enclosureID uuid.UUID
+	job         download.Job
+}
+
+func handlerDownload(s *state, cmd command, user database.User) error {
+	if len(cmd.args) > 0 {
+		switch cmd.args[0] {
+		case "auto":
+			return handlerDownloadAuto(s, cmd.args[1:], user)
+		case "played":
+			return handlerDownloadPlayed(s, cmd.args[1:])
+		case "verify":
+			return handlerDownloadVerify(s)
+		}
+	}
+
+	ctx := context.Background()
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	feedUrl := fs.String("feed", "", "only download episodes of the feed with this url")
+	concurrency := fs.Int("concurrency", s.cfg.DownloadWorkers(), "number of parallel downloads")
+	args, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return err
+	}
+
+	downloadLimit := 10
+	if len(args) > 0 {
+		limit, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+
+		downloadLimit = limit
+	}
+
+	getPendingDownloadsParams := database.GetPendingDownloadsForUserParams{
+		UserID:     user.ID,
+		FeedUrl:    *feedUrl,
+		LimitCount: int32(downloadLimit),
+	}
+	pending, err := s.db.GetPendingDownloadsForUser(ctx, getPendingDownloadsParams)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		fmt.Println("nothing to download")
+		return nil
+	}
+
+	var downloads []pendingDownload
+	for _, episode := range pending {
+		downloads = append(downloads, newPendingDownload(episode.FeedName, episode.PostTitle, episode.PublishedAt, database.Enclosure{
+			ID:       episode.ID,
+			Url:      episode.Url,
+			Length:   episode.Length,
+			MimeType: episode.MimeType,
+		}))
+	}
+
+	err = downloadEnclosures(ctx, s, downloads, *concurrency)
+	if err != nil {
+		return err
+	}
+
+	return enforceDownloadQuota(ctx, s)
+}
+
+// handlerDownloadAuto turns automatic downloads on or off for a feed. Like
+// the feed commands, only the user who added it or an admin may.
+func handlerDownloadAuto(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	if len(args) < 2 || (args[1] != "on" && args[1] != "off") {
+		return fmt.Errorf("usage: download auto <feed url> on|off")
+	}
+
+	feed, err := s.store.GetFeed(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("feed %s not found", args[0])
+	}
+	err = checkFeedOwner(s, feed, user)
+	if err != nil {
+		return err
+	}
+
+	setFeedAutoDownloadParams := database.SetFeedAutoDownloadParams{
+		Url:          feed.Url,
+		AutoDownload: args[1] == "on",
+	}
+	updated, err := s.store.SetFeedAutoDownload(ctx, setFeedAutoDownloadParams)
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return fmt.Errorf("feed %s not found", args[0])
+	}
+
+	fmt.Printf("automatic downloads for %s turned %s\n", args[0], args[1])
+	return nil
+}
+
+func handlerDownloadPlayed(s *state, args []string) error {
+	ctx := context.Background()
+
+	if len(args) == 0 {
+		return fmt.Errorf("must provide an episode or post url")
+	}
+
+	updated, err := s.db.MarkEnclosurePlayed(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return fmt.Errorf("episode %s not found", args[0])
+	}
+
+	fmt.Printf("marked %s as played\n", args[0])
+	return nil
+}
+
+// handlerDownloadVerify rehashes every downloaded file and forgets the ones
+// that are missing or no longer match their recorded checksum, so that the
+// next download run fetches them again.
+func handlerDownloadVerify(s *state) error {
+	ctx := context.Background()
+
+	downloaded, err := s.db.GetDownloadedEnclosures(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, enclosure := range downloaded {
+		size, checksum, err := download.Checksum(enclosure.LocalPath.String)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			fmt.Printf("missing: %s\n", enclosure.LocalPath.String)
+		case err != nil:
+			return err
+		case size != enclosure.DownloadedBytes.Int64 || checksum != enclosure.Sha256.String:
+			fmt.Printf("corrupt: %s\n", enclosure.LocalPath.String)
+			err = os.Remove(enclosure.LocalPath.String)
+			if err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+
+		err = s.db.ClearEnclosureDownload(ctx, enclosure.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("verified %d downloads\n", len(downloaded))
+	return nil
+}
+
+func newPendingDownload(feedName, postTitle string, publishedAt sql.NullTime, enclosure database.Enclosure) pendingDownload {
+	datePrefix := "undated"
+	if publishedAt.Valid {
+		datePrefix = publishedAt.Time.Format(time.DateOnly)
+	}
+
+	fileName := fmt.Sprintf("%s-%s-%s%s", datePrefix, slugify(postTitle), shortID(enclosure.ID), enclosureExtension(enclosure))
+
+	return pendingDownload{
+		enclosureID: enclosure.ID,
+		job: download.Job{
+			URL:          enclosure.Url,
+			Path:         filepath.Join(slugify(feedName), fileName),
+			ExpectedSize: enclosure.Length.Int64,
+		},
+	}
+}
+
+func downloadEnclosures(ctx context.Context, s *state, downloads []pendingDownload, concurrency int) error {
+	downloadDir, err := s.cfg.DownloadDirectory()
+	if err != nil {
+		return err
+	}
+
+	enclosureIDs := make(map[string]uuid.UUID)
+	var jobs []download.Job
+	for _, pending := range downloads {
+		enclosureIDs[pending.job.Path] = pending.enclosureID
+		jobs = append(jobs, pending.job)
+	}
+
+	connectTimeout, fetchTimeout, err := s.cfg.FetchTimeouts()
+	if err != nil {
+		return err
+	}
+
+	downloader := download.New(downloadDir, connectTimeout, fetchTimeout)
+	downloader.DownloadAll(ctx, jobs, concurrency, func(job download.Job, result download.Result, err error) {
+		if err != nil {
+			fmt.Printf("failed to download %s: %v\n", job.URL, err)
+			return
+		}
+
+		markEnclosureDownloadedParams := database.MarkEnclosureDownloadedParams{
+			ID: enclosureIDs[job.Path],
+			LocalPath: sql.NullString{
+				String: result.Path,
+				Valid:  true,
+			},
+			DownloadedBytes: sql.NullInt64{
+				Int64: result.Size,
+				Valid: true,
+			},
+			Sha256: sql.NullString{
+				String: result.SHA256,
+				Valid:  true,
+			},
+		}
+		err = s.db.MarkEnclosureDownloaded(ctx, markEnclosureDownloadedParams)
+		if err != nil {
+			fmt.Printf("downloaded %s but could not record it: %v\n", result.Path, err)
+			return
+		}
+
+		fmt.Printf("downloaded %s\n", result.Path)
+	})
+
+	return nil
+}
+
+// enforceDownloadQuota deletes downloaded files, played ones first and then
+// the oldest downloads, until the download directory is within the quota.
+func enforceDownloadQuota(ctx context.Context, s *state) error {
+	quota := s.cfg.DownloadQuotaBytes()
+	if quota <= 0 {
+		return nil
+	}
+
+	downloaded, err := s.db.GetDownloadedEnclosures(ctx)
+	if err != nil {
+		return err
+	}
+
+	var sizes []int64
+	for _, enclosure := range downloaded {
+		sizes = append(sizes, enclosure.DownloadedBytes.Int64)
+	}
+
+	evictions := download.Evictions(sizes, quota)
+	for _, enclosure := range downloaded[:evictions] {
+		err = os.Remove(enclosure.LocalPath.String)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		err = s.db.ClearEnclosureDownload(ctx, enclosure.ID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("removed %s to stay within the download quota\n", enclosure.LocalPath.String)
+	}
+
+	return nil
+}
+
+func enclosureExtension(enclosure database.Enclosure) string {
+	parsed, err := url.Parse(enclosure.Url)
+	if err == nil {
+		extension := path.Ext(parsed.Path)
+		if extension != "" && len(extension) <= 6 {
+			return strings.ToLower(extension)
+		}
+	}
+
+	extensions, err := mime.ExtensionsByType(enclosure.MimeType)
+	if err == nil && len(extensions) > 0 {
+		return extensions[0]
+	}
+
+	return ""
+}
+
+func slugify(value string) string {
+	var slug strings.Builder
+	lastDash := true
+	for _, r := range strings.ToLower(value) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			slug.WriteRune(r)
+			lastDash = false
+		case !lastDash:
+			slug.WriteRune('-')
+			lastDash = true
+		}
+
+		if slug.Len() >= 60 {
+			break
+		}
+	}
+
+	result := strings.Trim(slug.String(), "-")
+	if result == "" {
+		return "untitled"
+	}
+
+	return result
+}
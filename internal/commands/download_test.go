@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDownloadAutoChecksFeedOwner(t *testing.T) {
+	ctx := context.Background()
+	s := newSQLiteState(t)
+
+	owner := createTestUser(t, s, "alice")
+	other := createTestUser(t, s, "bob")
+	feed := createTestFeed(t, s, owner, "https://example.com/podcast.xml")
+
+	err := handlerDownload(s, NewCommand("download", []string{"auto", feed.Url, "on"}), other)
+	if err == nil {
+		t.Fatal("bob turned on automatic downloads for alice's feed")
+	}
+
+	err = handlerDownload(s, NewCommand("download", []string{"auto", feed.Url, "on"}), owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.store.GetFeed(ctx, feed.Url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.AutoDownload {
+		t.Fatal("the owner could not turn on automatic downloads")
+	}
+
+	err = handlerDownload(s, NewCommand("download", []string{"auto", "https://example.com/missing.xml", "on"}), owner)
+	if err == nil {
+		t.Error("turned on automatic downloads for a feed that does not exist")
+	}
+
+	// Admins may change any feed.
+	admin := other
+	admin.Role = roleAdmin
+	err = handlerDownload(s, NewCommand("download", []string{"auto", feed.Url, "off"}), admin)
+	if err != nil {
+		t.Fatalf("an admin could not change alice's feed: %v", err)
+	}
+
+	got, err = s.store.GetFeed(ctx, feed.Url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AutoDownload {
+		t.Errorf("feed = %+v, want automatic downloads off", got)
+	}
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+)
+
+func handlerEpisodes(s *state, cmd command, user database.User) error {
+	ctx := context.Background()
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	feedUrl := fs.String("feed", "", "only list episodes of the feed with this url")
+	args, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return err
+	}
+
+	episodeLimit := 10
+	if len(args) > 0 {
+		limit, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+
+		episodeLimit = limit
+	}
+
+	getEpisodesParams := database.GetEpisodesForUserParams{
+		UserID:     user.ID,
+		FeedUrl:    *feedUrl,
+		LimitCount: int32(episodeLimit),
+	}
+	episodes, err := s.db.GetEpisodesForUser(ctx, getEpisodesParams)
+	if err != nil {
+		return err
+	}
+
+	for _, episode := range episodes {
+		fmt.Printf("* %s: %s\n", episode.FeedName, episode.PostTitle)
+
+		details := episode.MimeType
+		if episode.PublishedAt.Valid {
+			details = episode.PublishedAt.Time.Format(time.DateOnly) + ", " + details
+		}
+		if episode.DurationSeconds.Valid {
+			details += ", " + (time.Duration(episode.DurationSeconds.Int32) * time.Second).String()
+		}
+		if episode.Length.Valid && episode.Length.Int64 > 0 {
+			details += fmt.Sprintf(", %.1f MB", float64(episode.Length.Int64)/1e6)
+		}
+		fmt.Printf("  %s\n", details)
+		fmt.Printf("  %s\n", episode.Url)
+		if episode.ImageUrl.Valid {
+			fmt.Printf("  art: %s\n", episode.ImageUrl.String)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,342 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/ctiller15/gator/internal/storage"
+)
+
+func handlerFeed(s *state, cmd command, user database.User) error {
+	if len(cmd.args) == 0 {
+		return fmt.Errorf("usage: feed rename|seturl|interval|delete|show")
+	}
+
+	args := cmd.args[1:]
+	switch cmd.args[0] {
+	case "rename":
+		return handlerFeedRename(s, args, user)
+	case "seturl":
+		return handlerFeedSetUrl(s, args, user)
+	case "interval":
+		return handlerFeedInterval(s, args, user)
+	case "delete":
+		return handlerFeedDelete(s, args, user)
+	case "show":
+		return handlerFeedShow(s, args)
+	default:
+		return fmt.Errorf("unknown feed command %s", cmd.args[0])
+	}
+}
+
+func handlerFeedRename(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	if len(args) < 2 {
+		return fmt.Errorf("must provide both a feed url and a new name")
+	}
+
+	feed, err := s.store.GetFeed(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("no feed with url %s", args[0])
+	}
+	err = checkFeedOwner(s, feed, user)
+	if err != nil {
+		return err
+	}
+
+	renameFeedParams := database.RenameFeedParams{
+		Url:  feed.Url,
+		Name: args[1],
+	}
+	_, err = s.store.RenameFeed(ctx, renameFeedParams)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("feed %s is now called %s\n", args[0], args[1])
+	return nil
+}
+
+// handlerFeedSetUrl changes the url of a feed. If another feed already uses
+// the new url, the two are merged: followers, posts, filter rules and
+// webhooks move to the existing feed and the old one is deleted.
+func handlerFeedSetUrl(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	if len(args) < 2 {
+		return fmt.Errorf("must provide both the old and the new feed url")
+	}
+
+	oldUrl := args[0]
+	newUrl := args[1]
+	if oldUrl == newUrl {
+		return nil
+	}
+
+	oldFeed, err := s.store.GetFeed(ctx, oldUrl)
+	if err != nil {
+		return fmt.Errorf("no feed with url %s", oldUrl)
+	}
+	err = checkFeedOwner(s, oldFeed, user)
+	if err != nil {
+		return err
+	}
+
+	newFeed, err := s.store.GetFeed(ctx, newUrl)
+	if errors.Is(err, sql.ErrNoRows) {
+		setFeedUrlParams := database.SetFeedUrlParams{
+			NewUrl: newUrl,
+			OldUrl: oldUrl,
+		}
+		_, err = s.store.SetFeedUrl(ctx, setFeedUrlParams)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("feed %s now uses %s\n", oldFeed.Name, newUrl)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if s.conn == nil {
+		return fmt.Errorf("a feed with url %s already exists and merging feeds is %w", newUrl, storage.ErrNotSupported)
+	}
+
+	err = s.conn.InTx(ctx, func(q *database.Queries) error {
+		// Users who already follow the new feed keep their follow of it;
+		// their follow of the old feed goes away with the old feed.
+		err := q.MergeFeedFollows(ctx, database.MergeFeedFollowsParams{ToFeedID: newFeed.ID, FromFeedID: oldFeed.ID})
+		if err != nil {
+			return err
+		}
+
+		err = q.MoveFeedPosts(ctx, database.MoveFeedPostsParams{ToFeedID: newFeed.ID, FromFeedID: oldFeed.ID})
+		if err != nil {
+			return err
+		}
+
+		err = q.MoveFeedFilterRules(ctx, database.MoveFeedFilterRulesParams{ToFeedID: newFeed.ID, FromFeedID: oldFeed.ID})
+		if err != nil {
+			return err
+		}
+
+		err = q.MoveFeedWebhooks(ctx, database.MoveFeedWebhooksParams{ToFeedID: newFeed.ID, FromFeedID: oldFeed.ID})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.DeleteFeedByUrl(ctx, oldUrl)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("feed %s has been merged into %s (%s)\n", oldFeed.Name, newFeed.Name, newUrl)
+	return nil
+}
+
+// handlerFeedInterval sets how often agg fetches a feed, replacing the
+// interval worked out from its posting frequency, or goes back to that
+// interval when given "auto".
+func handlerFeedInterval(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	if len(args) < 2 {
+		return fmt.Errorf("must provide a feed url and an interval such as 2h, or auto")
+	}
+
+	feed, err := s.store.GetFeed(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("no feed with url %s", args[0])
+	}
+	err = checkFeedOwner(s, feed, user)
+	if err != nil {
+		return err
+	}
+
+	var interval sql.NullInt32
+	if args[1] != "auto" {
+		duration, err := time.ParseDuration(args[1])
+		if err != nil || duration < time.Minute {
+			return fmt.Errorf("interval must be a duration of at least 1m, such as 30m or 6h, or auto")
+		}
+		interval = sql.NullInt32{
+			Int32: int32(duration / time.Second),
+			Valid: true,
+		}
+	}
+
+	setFeedFetchIntervalParams := database.SetFeedFetchIntervalParams{
+		Url:                  feed.Url,
+		FetchIntervalSeconds: interval,
+	}
+	_, err = s.store.SetFeedFetchInterval(ctx, setFeedFetchIntervalParams)
+	if err != nil {
+		return err
+	}
+
+	if interval.Valid {
+		fmt.Printf("%s is now fetched every %s\n", feed.Name, args[1])
+	} else {
+		fmt.Printf("%s is now fetched as often as it publishes\n", feed.Name)
+	}
+	return nil
+}
+
+func handlerFeedDelete(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	fs := flag.NewFlagSet("feed delete", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) < 1 {
+		return fmt.Errorf("must provide a feed url")
+	}
+
+	feed, err := s.store.GetFeed(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("no feed with url %s", args[0])
+	}
+	err = checkFeedOwner(s, feed, user)
+	if err != nil {
+		return err
+	}
+
+	if !*yes {
+		stats, err := s.store.GetFeedStats(ctx, feed.ID)
+		if err != nil {
+			return err
+		}
+
+		question := fmt.Sprintf("this deletes %s with %d followers and %d posts, continue?", feed.Name, stats.Followers, stats.Posts)
+		ok, err := confirm(question)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("delete cancelled")
+			return nil
+		}
+	}
+
+	_, err = s.store.DeleteFeedByUrl(ctx, feed.Url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("feed %s has been deleted\n", feed.Name)
+	return nil
+}
+
+func handlerFeedShow(s *state, args []string) error {
+	ctx := context.Background()
+
+	if len(args) < 1 {
+		return fmt.Errorf("must provide a feed url")
+	}
+
+	feed, err := s.store.GetFeed(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("no feed with url %s", args[0])
+	}
+
+	stats, err := s.store.GetFeedStats(ctx, feed.ID)
+	if err != nil {
+		return err
+	}
+
+	kind := "feed"
+	if feed.IsPodcast {
+		kind = "podcast"
+		if feed.AutoDownload {
+			kind = "podcast, downloaded automatically"
+		}
+	}
+
+	fmt.Printf("%s\n", feed.Name)
+	fmt.Printf("  url:          %s\n", feed.Url)
+	fmt.Printf("  id:           %s\n", feed.ID)
+	fmt.Printf("  added:        %s by %s\n", feed.CreatedAt.Local().Format(time.DateTime), feedOwnerName(ctx, s, feed))
+	fmt.Printf("  type:         %s\n", kind)
+	fmt.Printf("  followers:    %d\n", stats.Followers)
+	fmt.Printf("  posts:        %d\n", stats.Posts)
+	fmt.Printf("  last fetch:   %s\n", lastFetchResult(feed))
+	fmt.Printf("  interval:     %s\n", fetchInterval(feed))
+	fmt.Printf("  next fetch:   %s\n", nextFetch(feed))
+	return nil
+}
+
+// checkFeedOwner lets user change feed only if they added it or are an
+// admin. Feeds without an owner, because they were added before owners were
+// recorded or their owner has been deleted, may be changed by anyone.
+func checkFeedOwner(s *state, feed database.Feed, user database.User) error {
+	if !feed.AddedBy.Valid || feed.AddedBy.UUID == user.ID || user.Role == roleAdmin {
+		return nil
+	}
+
+	return fmt.Errorf("feed %s was added by %s, only they can change it", feed.Url, feedOwnerName(context.Background(), s, feed))
+}
+
+func feedOwnerName(ctx context.Context, s *state, feed database.Feed) string {
+	if !feed.AddedBy.Valid {
+		return "unknown"
+	}
+
+	users, err := s.store.GetUsers(ctx)
+	if err != nil {
+		return "unknown"
+	}
+	for _, user := range users {
+		if user.ID == feed.AddedBy.UUID {
+			return user.Name
+		}
+	}
+
+	return "unknown"
+}
+
+func lastFetchResult(feed database.Feed) string {
+	if !feed.LastFetchedAt.Valid {
+		return "never"
+	}
+
+	fetchedAt := feed.LastFetchedAt.Time.Local().Format(time.DateTime)
+	switch {
+	case !feed.LastFetchStatus.Valid:
+		return fetchedAt
+	case feed.LastFetchStatus.String == fetchStatusOK:
+		return fmt.Sprintf("%s, ok, %d new posts", fetchedAt, feed.LastFetchNewPosts.Int32)
+	case feed.LastFetchError.Valid:
+		return fmt.Sprintf("%s, %s: %s", fetchedAt, feed.LastFetchStatus.String, feed.LastFetchError.String)
+	default:
+		return fmt.Sprintf("%s, %s", fetchedAt, feed.LastFetchStatus.String)
+	}
+}
+
+func nextFetch(feed database.Feed) string {
+	if !feed.NextFetchAt.Valid || !feed.NextFetchAt.Time.After(time.Now()) {
+		return "on the next run of agg"
+	}
+
+	return "not before " + feed.NextFetchAt.Time.Local().Format(time.DateTime)
+}
+
+func fetchInterval(feed database.Feed) string {
+	if !feed.FetchIntervalSeconds.Valid {
+		return "auto, from how often it publishes"
+	}
+
+	return (time.Duration(feed.FetchIntervalSeconds.Int32) * time.Second).String()
+}
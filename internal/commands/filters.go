@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/google/uuid"
+)
+
+var (
+	filterActions = []string{"include", "exclude"}
+	filterFields  = []string{"title", "description", "author", "category", "feed"}
+)
+
+func handlerFilter(s *state, cmd command, user database.User) error {
+	if len(cmd.args) == 0 {
+		return fmt.Errorf("usage: filter add|list|rm")
+	}
+
+	args := cmd.args[1:]
+	switch cmd.args[0] {
+	case "add":
+		return handlerFilterAdd(s, args, user)
+	case "list":
+		return handlerFilterList(s, user)
+	case "rm":
+		return handlerFilterRemove(s, args, user)
+	default:
+		return fmt.Errorf("unknown filter command %s", cmd.args[0])
+	}
+}
+
+func handlerFilterAdd(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	fs := flag.NewFlagSet("filter add", flag.ContinueOnError)
+	isRegex := fs.Bool("regex", false, "treat the pattern as a regular expression")
+	feedUrl := fs.String("feed", "", "only apply the rule to the feed with this url")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) < 3 {
+		return fmt.Errorf("usage: filter add include|exclude <%s> <pattern> [--regex] [--feed <url>]", strings.Join(filterFields, "|"))
+	}
+
+	action, field, pattern := args[0], args[1], strings.Join(args[2:], " ")
+	if !slices.Contains(filterActions, action) {
+		return fmt.Errorf("action must be one of %s", strings.Join(filterActions, ", "))
+	}
+	if !slices.Contains(filterFields, field) {
+		return fmt.Errorf("field must be one of %s", strings.Join(filterFields, ", "))
+	}
+
+	if *isRegex {
+		err = checkFilterPattern(ctx, s, pattern)
+		if err != nil {
+			return err
+		}
+	}
+
+	feedID := uuid.NullUUID{}
+	if *feedUrl != "" {
+		feed, err := s.store.GetFeedByUrl(ctx, *feedUrl)
+		if err != nil {
+			return fmt.Errorf("feed %s not found: %w", *feedUrl, err)
+		}
+
+		feedID = uuid.NullUUID{
+			UUID:  feed.ID,
+			Valid: true,
+		}
+	}
+
+	currentTime := time.Now()
+	createFilterRuleParams := database.CreateFilterRuleParams{
+		ID:        uuid.New(),
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+		UserID:    user.ID,
+		Action:    action,
+		Field:     field,
+		Pattern:   pattern,
+		IsRegex:   *isRegex,
+		FeedID:    feedID,
+	}
+	rule, err := s.db.CreateFilterRule(ctx, createFilterRuleParams)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("filter %s has been added\n", shortID(rule.ID))
+	return nil
+}
+
+func handlerFilterList(s *state, user database.User) error {
+	ctx := context.Background()
+
+	rules, err := s.db.GetFilterRulesForUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		matcher := "contains"
+		if rule.IsRegex {
+			matcher = "matches"
+		}
+
+		scope := ""
+		if rule.FeedUrl.Valid {
+			scope = " on " + rule.FeedUrl.String
+		}
+
+		fmt.Printf("* %s: %s %s %s %q%s\n", shortID(rule.ID), rule.Action, rule.Field, matcher, rule.Pattern, scope)
+	}
+
+	return nil
+}
+
+// handlerFilterRemove deletes a rule by its id, or by any prefix of its id
+// that identifies it unambiguously, such as the short id filter list shows.
+func handlerFilterRemove(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	if len(args) == 0 {
+		return fmt.Errorf("must provide a filter id")
+	}
+
+	rules, err := s.db.GetFilterRulesForUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	var matches []uuid.UUID
+	for _, rule := range rules {
+		if strings.HasPrefix(rule.ID.String(), strings.ToLower(args[0])) {
+			matches = append(matches, rule.ID)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("filter %s not found", args[0])
+	case 1:
+	default:
+		return fmt.Errorf("filter id %s is ambiguous", args[0])
+	}
+
+	deleteFilterRuleParams := database.DeleteFilterRuleParams{
+		ID:     matches[0],
+		UserID: user.ID,
+	}
+	_, err = s.db.DeleteFilterRule(ctx, deleteFilterRuleParams)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("filter %s has been removed\n", shortID(matches[0]))
+	return nil
+}
+
+func shortID(id uuid.UUID) string {
+	return id.String()[:8]
+}
+
+// checkFilterPattern makes sure pattern compiles where browse and digest will
+// run it. Postgres evaluates it with its own regular expression engine,
+// which rejects some syntax Go accepts, such as (?P<name>...) or \z, so it
+// is tried there too rather than failing every later query.
+func checkFilterPattern(ctx context.Context, s *state, pattern string) error {
+	_, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return fmt.Errorf("invalid regular expression: %w", err)
+	}
+
+	if s.db == nil {
+		return nil
+	}
+
+	_, err = s.db.CheckFilterPattern(ctx, pattern)
+	if err != nil {
+		return fmt.Errorf("invalid regular expression for this database: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"flag"
+	"io"
+)
+
+// parseFlags parses the flags registered on fs, allowing them to appear
+// before, between or after positional arguments, and returns the positional
+// arguments in their original order.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	fs.SetOutput(io.Discard)
+
+	var positional []string
+	for {
+		err := fs.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
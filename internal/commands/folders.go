@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/google/uuid"
+)
+
+func handlerFolder(s *state, cmd command, user database.User) error {
+	if len(cmd.args) == 0 {
+		return fmt.Errorf("usage: folder create|add|rm|list")
+	}
+
+	args := cmd.args[1:]
+	switch cmd.args[0] {
+	case "create":
+		return handlerFolderCreate(s, args, user)
+	case "add":
+		return handlerFolderAdd(s, args, user)
+	case "rm":
+		return handlerFolderRemove(s, args, user)
+	case "list":
+		return handlerFolderList(s, user)
+	default:
+		return fmt.Errorf("unknown folder command %s", cmd.args[0])
+	}
+}
+
+func handlerFolderCreate(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	if len(args) == 0 {
+		return fmt.Errorf("must provide a folder name")
+	}
+
+	currentTime := time.Now()
+	createFolderParams := database.CreateFolderParams{
+		ID:        uuid.New(),
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+		UserID:    user.ID,
+		Name:      args[0],
+	}
+	folder, err := s.db.CreateFolder(ctx, createFolderParams)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("folder %s has been created\n", folder.Name)
+	return nil
+}
+
+func handlerFolderAdd(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	if len(args) < 2 {
+		return fmt.Errorf("must provide both a folder name and a feed url")
+	}
+
+	folder, err := getFolder(ctx, s, user, args[0])
+	if err != nil {
+		return err
+	}
+
+	setFeedFollowFolderParams := database.SetFeedFollowFolderParams{
+		FolderID: uuid.NullUUID{
+			UUID:  folder.ID,
+			Valid: true,
+		},
+		UserID: user.ID,
+		Url:    args[1],
+	}
+	updated, err := s.db.SetFeedFollowFolder(ctx, setFeedFollowFolderParams)
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return fmt.Errorf("you are not following %s", args[1])
+	}
+
+	fmt.Printf("moved %s to %s\n", args[1], folder.Name)
+	return nil
+}
+
+// handlerFolderRemove deletes a folder, or with a feed url only takes that
+// feed out of the folder. Feeds in a deleted folder stay followed.
+func handlerFolderRemove(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	if len(args) == 0 {
+		return fmt.Errorf("must provide a folder name")
+	}
+
+	if len(args) > 1 {
+		folder, err := getFolder(ctx, s, user, args[0])
+		if err != nil {
+			return err
+		}
+
+		removeFeedFollowParams := database.RemoveFeedFollowFromFolderParams{
+			UserID: user.ID,
+			FolderID: uuid.NullUUID{
+				UUID:  folder.ID,
+				Valid: true,
+			},
+			Url: args[1],
+		}
+		updated, err := s.db.RemoveFeedFollowFromFolder(ctx, removeFeedFollowParams)
+		if err != nil {
+			return err
+		}
+
+		if updated == 0 {
+			return fmt.Errorf("%s is not in folder %s", args[1], folder.Name)
+		}
+
+		fmt.Printf("removed %s from %s\n", args[1], folder.Name)
+		return nil
+	}
+
+	deleteFolderParams := database.DeleteFolderParams{
+		UserID: user.ID,
+		Name:   args[0],
+	}
+	deleted, err := s.db.DeleteFolder(ctx, deleteFolderParams)
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return fmt.Errorf("folder %s not found", args[0])
+	}
+
+	fmt.Printf("folder %s has been deleted\n", args[0])
+	return nil
+}
+
+func handlerFolderList(s *state, user database.User) error {
+	ctx := context.Background()
+
+	folders, err := s.db.GetFoldersForUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, folder := range folders {
+		fmt.Printf("* %s\n", folder.Name)
+	}
+
+	return nil
+}
+
+func getFolder(ctx context.Context, s *state, user database.User, name string) (database.Folder, error) {
+	getFolderByNameParams := database.GetFolderByNameParams{
+		UserID: user.ID,
+		Name:   name,
+	}
+	folder, err := s.db.GetFolderByName(ctx, getFolderByNameParams)
+	if err != nil {
+		return database.Folder{}, fmt.Errorf("folder %s not found: %w", name, err)
+	}
+
+	return folder, nil
+}
@@ -0,0 +1,330 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ctiller15/gator/internal/config"
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/ctiller15/gator/internal/rss"
+	"github.com/ctiller15/gator/internal/storage"
+)
+
+// newMemoryState returns a state backed by a storage.Memory, with a config
+// file of its own so login and register can record the current user.
+func newMemoryState(t *testing.T) *state {
+	t.Helper()
+
+	cfg, err := config.Read(config.Options{Path: filepath.Join(t.TempDir(), "config.json")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewState(&cfg, storage.NewMemory(), nil)
+}
+
+// run runs a command the way main does, permission checks included.
+func run(s *state, name string, args ...string) error {
+	return NewCommands().Run(s, NewCommand(name, args))
+}
+
+func mustRun(t *testing.T, s *state, name string, args ...string) {
+	t.Helper()
+
+	err := run(s, name, args...)
+	if err != nil {
+		t.Fatalf("%s %s: %v", name, strings.Join(args, " "), err)
+	}
+}
+
+func TestRegisterAndLoginOnMemory(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryState(t)
+
+	mustRun(t, s, "register", "alice")
+	mustRun(t, s, "register", "bob")
+	if s.cfg.CurrentUserName != "bob" {
+		t.Errorf("current user = %q after register, want bob", s.cfg.CurrentUserName)
+	}
+
+	if err := run(s, "register", "bob"); !errors.Is(err, storage.ErrDuplicate) {
+		t.Errorf("registering bob twice: %v, want ErrDuplicate", err)
+	}
+
+	alice, err := s.store.GetUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := s.store.GetUser(ctx, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice.Role != roleAdmin || bob.Role != roleMember {
+		t.Errorf("roles are alice=%s, bob=%s, want only the first user to be an admin", alice.Role, bob.Role)
+	}
+
+	if err := run(s, "users"); err == nil {
+		t.Error("a member could list users")
+	}
+	mustRun(t, s, "login", "alice")
+	mustRun(t, s, "users")
+
+	if err := run(s, "login", "carol"); err == nil {
+		t.Error("logged in as a user that does not exist")
+	}
+}
+
+func TestMigrateRequiresAdmin(t *testing.T) {
+	s := newMemoryState(t)
+
+	// With no users yet, anyone may migrate a fresh install.
+	if err := run(s, "migrate", "up"); !errors.Is(err, storage.ErrNotSupported) {
+		t.Errorf("migrate before the first user: %v, want it let through to the backend", err)
+	}
+
+	mustRun(t, s, "register", "alice")
+	mustRun(t, s, "register", "bob")
+
+	err := run(s, "migrate", "down")
+	if err == nil || errors.Is(err, storage.ErrNotSupported) {
+		t.Errorf("member ran migrate: %v, want a permission error", err)
+	}
+}
+
+func TestFollowAndBrowseOnMemory(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryState(t)
+
+	mustRun(t, s, "register", "alice")
+	mustRun(t, s, "addfeed", "Example", "https://example.com/feed.xml")
+	mustRun(t, s, "register", "bob")
+	mustRun(t, s, "follow", "https://example.com/feed.xml")
+	mustRun(t, s, "following")
+
+	bob, err := s.store.GetUser(ctx, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed, err := s.store.GetFeed(ctx, "https://example.com/feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	createTestPost(t, s, feed, "Hello")
+
+	stats, err := s.store.GetUserStats(ctx, database.GetUserStatsParams{UserID: bob.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Follows != 1 || stats.Posts != 1 || stats.RecentPosts != 1 {
+		t.Errorf("GetUserStats() = %+v, want 1 follow and 1 recent post", stats)
+	}
+	mustRun(t, s, "user", "show")
+
+	mustRun(t, s, "browse", "10")
+	if err := run(s, "browse", "--folder", "news"); !errors.Is(err, storage.ErrNotSupported) {
+		t.Errorf("browse --folder: %v, want ErrNotSupported", err)
+	}
+
+	mustRun(t, s, "unfollow", "https://example.com/feed.xml")
+	follows, err := s.store.GetFeedFollowsForUser(ctx, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(follows) != 0 {
+		t.Errorf("bob still follows %d feeds after unfollow", len(follows))
+	}
+}
+
+func TestFeedOwnerOnMemory(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryState(t)
+
+	mustRun(t, s, "register", "alice")
+	mustRun(t, s, "register", "bob")
+	mustRun(t, s, "addfeed", "Bob's", "https://bob.example.com/feed.xml")
+	mustRun(t, s, "register", "carol")
+
+	if err := run(s, "feed", "rename", "https://bob.example.com/feed.xml", "Carol's"); err == nil {
+		t.Error("carol renamed bob's feed")
+	}
+
+	// Admins may change any feed.
+	mustRun(t, s, "login", "alice")
+	mustRun(t, s, "feed", "rename", "https://bob.example.com/feed.xml", "Renamed")
+	mustRun(t, s, "feed", "interval", "https://bob.example.com/feed.xml", "2h")
+	mustRun(t, s, "feed", "show", "https://bob.example.com/feed.xml")
+
+	feed, err := s.store.GetFeed(ctx, "https://bob.example.com/feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.Name != "Renamed" || feed.FetchIntervalSeconds.Int32 != 7200 {
+		t.Errorf("feed = %+v, want it renamed with a 2h interval", feed)
+	}
+
+	mustRun(t, s, "addfeed", "Other", "https://other.example.com/feed.xml")
+	if err := run(s, "feed", "seturl", "https://bob.example.com/feed.xml", "https://other.example.com/feed.xml"); !errors.Is(err, storage.ErrNotSupported) {
+		t.Errorf("merging feeds: %v, want ErrNotSupported", err)
+	}
+
+	mustRun(t, s, "feed", "delete", "--yes", "https://bob.example.com/feed.xml")
+	if _, err := s.store.GetFeed(ctx, "https://bob.example.com/feed.xml"); err == nil {
+		t.Error("feed still exists after delete")
+	}
+}
+
+func TestLastAdminOnMemory(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryState(t)
+
+	mustRun(t, s, "register", "alice")
+	mustRun(t, s, "register", "bob")
+	mustRun(t, s, "login", "alice")
+
+	if err := run(s, "admin", "revoke", "alice"); err == nil {
+		t.Error("the only admin revoked their own role")
+	}
+	if err := run(s, "user", "delete", "--yes", "alice"); err == nil {
+		t.Error("the only admin deleted themselves")
+	}
+
+	mustRun(t, s, "admin", "grant", "bob")
+	mustRun(t, s, "user", "delete", "--yes", "alice")
+
+	users, err := s.store.GetUsers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Name != "bob" || users[0].Role != roleAdmin {
+		t.Errorf("users = %+v, want only bob as an admin", users)
+	}
+}
+
+func TestSQLCommandsOnMemory(t *testing.T) {
+	s := newMemoryState(t)
+	mustRun(t, s, "register", "alice")
+
+	for name := range sqlCommands {
+		err := run(s, name)
+		if !errors.Is(err, storage.ErrNotSupported) {
+			t.Errorf("%s: %v, want ErrNotSupported", name, err)
+		}
+	}
+}
+
+const testFeed = `<?xml version="1.0"?>
+<rss version="2.0">
+<channel>
+<title>Example</title>
+<link>https://example.com/</link>
+<description>An example feed</description>
+<item><title>First</title><link>https://example.com/1</link><pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate><category>Go</category></item>
+<item><title>Second</title><link>https://example.com/2</link><pubDate>Tue, 03 Jan 2006 15:04:05 GMT</pubDate></item>
+</channel>
+</rss>`
+
+func TestScrapeFeedsOnMemory(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryState(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feed.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(testFeed))
+	}))
+	defer server.Close()
+
+	fetcher, err := rss.NewFetcher(rss.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := createTestUser(t, s, "alice")
+	feed := createTestFeed(t, s, user, server.URL+"/feed.xml")
+
+	err = scrapeFeeds(ctx, s, fetcher, newWebhookSender(webhookWorkers))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetched, err := s.store.GetFeed(ctx, feed.Url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched.LastFetchStatus.String != fetchStatusOK || fetched.LastFetchNewPosts.Int32 != 2 {
+		t.Errorf("fetch recorded as %q with %d new posts, want ok with 2", fetched.LastFetchStatus.String, fetched.LastFetchNewPosts.Int32)
+	}
+	if !fetched.LastFetchedAt.Valid || !fetched.NextFetchAt.Valid || !fetched.NextFetchAt.Time.After(fetched.LastFetchedAt.Time) {
+		t.Errorf("fetched at %v, next fetch at %v, want the next fetch scheduled later", fetched.LastFetchedAt, fetched.NextFetchAt)
+	}
+
+	posts, err := s.store.GetPostsForUser(ctx, database.GetPostsForUserParams{ID: user.ID, LimitCount: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 2 || posts[0].Title != "Second" || posts[1].Title != "First" {
+		t.Fatalf("posts = %+v, want Second then First", posts)
+	}
+
+	categories, err := s.store.GetTopCategoriesForUser(ctx, database.GetTopCategoriesForUserParams{UserID: user.ID, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(categories) != 1 || categories[0].Name != "go" {
+		t.Errorf("categories = %+v, want go", categories)
+	}
+
+	// The feed is not due again, so there is nothing to do.
+	err = scrapeFeeds(ctx, s, fetcher, newWebhookSender(webhookWorkers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	posts, err = s.store.GetPostsForUser(ctx, database.GetPostsForUserParams{ID: user.ID, LimitCount: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 2 {
+		t.Errorf("got %d posts after a second scrape, want 2", len(posts))
+	}
+}
+
+func TestScrapeFeedsRecordsErrorsOnMemory(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryState(t)
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	fetcher, err := rss.NewFetcher(rss.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := createTestUser(t, s, "alice")
+	feed := createTestFeed(t, s, user, server.URL+"/missing.xml")
+
+	// A broken feed is recorded rather than stopping agg.
+	err = scrapeFeeds(ctx, s, fetcher, newWebhookSender(webhookWorkers))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetched, err := s.store.GetFeed(ctx, feed.Url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched.LastFetchStatus.String != fetchStatusError || !strings.Contains(fetched.LastFetchError.String, "Not Found") {
+		t.Errorf("fetch recorded as %q: %q, want Not Found", fetched.LastFetchStatus.String, fetched.LastFetchError.String)
+	}
+	if !fetched.NextFetchAt.Valid {
+		t.Error("no retry scheduled for the broken feed")
+	}
+}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ctiller15/gator/internal/migrate"
+	"github.com/pressly/goose/v3"
+)
+
+func handlerMigrate(s *state, cmd command) error {
+	ctx := context.Background()
+
+	if len(cmd.args) == 0 {
+		return fmt.Errorf("usage: migrate up|down|status|version")
+	}
+
+	provider, err := migrate.NewProvider(s.conn)
+	if err != nil {
+		return err
+	}
+
+	args := cmd.args[1:]
+	switch cmd.args[0] {
+	case "up":
+		return handlerMigrateUp(ctx, provider, args)
+	case "down":
+		return handlerMigrateDown(ctx, provider, args)
+	case "status":
+		return handlerMigrateStatus(ctx, provider)
+	case "version":
+		return handlerMigrateVersion(ctx, provider)
+	default:
+		return fmt.Errorf("unknown migrate command %s", cmd.args[0])
+	}
+}
+
+// handlerMigrateUp applies all pending migrations, or those up to and
+// including the given version.
+func handlerMigrateUp(ctx context.Context, provider *goose.Provider, args []string) error {
+	var results []*goose.MigrationResult
+	var err error
+	if len(args) > 0 {
+		version, parseErr := strconv.ParseInt(args[0], 10, 64)
+		if parseErr != nil {
+			return fmt.Errorf("invalid version %s", args[0])
+		}
+		results, err = provider.UpTo(ctx, version)
+	} else {
+		results, err = provider.Up(ctx)
+	}
+	printMigrationResults(results)
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		fmt.Println("schema is up to date")
+	}
+
+	return nil
+}
+
+// handlerMigrateDown rolls back the newest migration, or every migration
+// newer than the given version.
+func handlerMigrateDown(ctx context.Context, provider *goose.Provider, args []string) error {
+	if len(args) > 0 {
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %s", args[0])
+		}
+
+		results, err := provider.DownTo(ctx, version)
+		printMigrationResults(results)
+		return err
+	}
+
+	result, err := provider.Down(ctx)
+	if result != nil {
+		printMigrationResults([]*goose.MigrationResult{result})
+	}
+
+	return err
+}
+
+func handlerMigrateStatus(ctx context.Context, provider *goose.Provider) error {
+	statuses, err := provider.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.State == goose.StateApplied {
+			appliedAt = status.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%-19s  %s\n", appliedAt, status.Source.Path)
+	}
+
+	return nil
+}
+
+func handlerMigrateVersion(ctx context.Context, provider *goose.Provider) error {
+	current, target, err := provider.GetVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("schema version %d (latest %d)\n", current, target)
+	return nil
+}
+
+func printMigrationResults(results []*goose.MigrationResult) {
+	for _, result := range results {
+		fmt.Println(result)
+	}
+}
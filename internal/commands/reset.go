@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ctiller15/gator/internal/backup"
+	"github.com/ctiller15/gator/internal/database"
+)
+
+func handlerReset(s *state, cmd command) error {
+	ctx := context.Background()
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	users := fs.Bool("users", false, "delete all users with their follows, folders, filters and webhooks")
+	feeds := fs.Bool("feeds", false, "delete all feeds with their follows and posts")
+	posts := fs.Bool("posts", false, "delete all collected posts")
+	all := fs.Bool("all", false, "delete users and feeds")
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	_, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return err
+	}
+
+	if *all {
+		*users = true
+		*feeds = true
+	}
+	if !*users && !*feeds && !*posts {
+		return fmt.Errorf("choose what to reset: --users, --feeds, --posts or --all")
+	}
+
+	counts, err := s.db.CountRows(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !*yes {
+		question := fmt.Sprintf("this deletes %s, continue?", resetSummary(counts, *users, *feeds, *posts))
+		ok, err := confirm(question)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("reset cancelled")
+			return nil
+		}
+	}
+
+	backupDir, err := s.cfg.BackupDirectory()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(backupDir, 0700)
+	if err != nil {
+		return err
+	}
+	backupFile, err := os.CreateTemp(backupDir, fmt.Sprintf("reset-%s-*.json", time.Now().Format("20060102-150405")))
+	if err != nil {
+		return err
+	}
+	defer backupFile.Close()
+	backupPath := backupFile.Name()
+
+	err = s.conn.InTx(ctx, func(q *database.Queries) error {
+		b, err := backup.Dump(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		err = backup.Encode(backupFile, b)
+		if err == nil {
+			err = backupFile.Close()
+		}
+		if err != nil {
+			return fmt.Errorf("couldn't save backup: %w", err)
+		}
+
+		if *users {
+			err = q.DeleteUser(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		if *feeds {
+			err = q.DeleteFeeds(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		if *posts {
+			err = q.DeletePosts(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		os.Remove(backupPath)
+		return err
+	}
+
+	fmt.Println("Deletion successful")
+	fmt.Printf("backup saved to %s, undo with: gator restore %s\n", backupPath, backupPath)
+	return nil
+}
+
+// resetSummary describes the rows a reset deletes, including those removed
+// by cascading deletes.
+func resetSummary(counts database.CountRowsRow, users, feeds, posts bool) string {
+	var parts []string
+	if users {
+		parts = append(parts, fmt.Sprintf("%d users", counts.Users))
+	}
+	if feeds {
+		parts = append(parts, fmt.Sprintf("%d feeds", counts.Feeds))
+	}
+	if users || feeds {
+		parts = append(parts, fmt.Sprintf("%d follows", counts.FeedFollows))
+	}
+	if feeds || posts {
+		parts = append(parts, fmt.Sprintf("%d posts", counts.Posts))
+	}
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+// confirm asks a yes/no question on the terminal. Anything but an answer
+// starting with y, including end of input, is a no.
+func confirm(question string) (bool, error) {
+	fmt.Printf("%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	if errors.Is(err, io.EOF) {
+		fmt.Println()
+	}
+
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "y"), nil
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ctiller15/gator/internal/config"
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/ctiller15/gator/internal/rss"
+)
+
+// publishSample is the number of recent posts pollInterval looks at to
+// estimate how often a feed publishes.
+const publishSample = 10
+
+// pollInterval returns how long agg waits before fetching feed again. Feeds
+// with an override use it as is. Otherwise the interval is half the average
+// time between the feed's recent posts, so a new post is picked up within
+// half a publishing cycle, or the time since its last post if it has gone
+// quiet, bounded by the configured min and max fetch intervals. Undated
+// posts only carry the time they were first seen, so they are left out, and
+// feeds with fewer than two dated posts are polled at the max interval.
+func pollInterval(ctx context.Context, s *state, feed database.Feed) (time.Duration, error) {
+	if feed.FetchIntervalSeconds.Valid {
+		return time.Duration(feed.FetchIntervalSeconds.Int32) * time.Second, nil
+	}
+
+	minInterval, maxInterval, err := s.cfg.FetchIntervals()
+	if err != nil {
+		return 0, err
+	}
+
+	getFeedPublishTimesParams := database.GetFeedPublishTimesParams{
+		FeedID: feed.ID,
+		Limit:  publishSample,
+	}
+	published, err := s.store.GetFeedPublishTimes(ctx, getFeedPublishTimesParams)
+	if err != nil {
+		return 0, err
+	}
+	if len(published) < 2 {
+		return maxInterval, nil
+	}
+
+	newest := published[0].Time
+	oldest := published[len(published)-1].Time
+	gap := newest.Sub(oldest) / time.Duration(len(published)-1)
+	gap = max(gap, time.Since(newest))
+
+	return min(max(gap/2, minInterval), maxInterval), nil
+}
+
+// untilNextFetch returns how long agg can sleep before a feed is due. Feeds
+// added while it sleeps are due at once, so it wakes at least every
+// min_fetch_interval to look for them.
+func untilNextFetch(ctx context.Context, s *state) (time.Duration, error) {
+	minInterval, _, err := s.cfg.FetchIntervals()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = s.store.GetNextFeedToFetch(ctx, sql.NullTime{Time: time.Now(), Valid: true})
+	if err == nil {
+		return 0, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	earliest, err := s.store.GetEarliestNextFetch(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return minInterval, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return min(max(time.Until(earliest.Time), 0), minInterval), nil
+}
+
+// newFetcher returns the feed fetcher configured in cfg, with its per-host
+// rate limiter and, when check_robots_txt is set, its robots.txt checker.
+func newFetcher(cfg *config.Config) (*rss.Fetcher, error) {
+	defaults, hosts, err := cfg.HostLimits()
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make(map[string]rss.Limit, len(hosts))
+	for host, limit := range hosts {
+		limits[host] = rss.Limit(limit)
+	}
+
+	connectTimeout, fetchTimeout, err := cfg.FetchTimeouts()
+	if err != nil {
+		return nil, err
+	}
+
+	options := rss.Options{
+		ConnectTimeout: connectTimeout,
+		Timeout:        fetchTimeout,
+		MaxBodySize:    cfg.MaxFeedSizeBytes(),
+		MaxRedirects:   cfg.FetchRedirects(),
+		Proxy:          cfg.ProxyURL,
+		ContactURL:     cfg.ContactURL,
+		Limiter:        rss.NewLimiter(rss.Limit(defaults), limits),
+		CheckRobots:    cfg.CheckRobotsTxt,
+	}
+	return rss.NewFetcher(options)
+}
@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestGetNextFeedToFetchOrder(t *testing.T) {
+	for name, newState := range map[string]func(*testing.T) *state{
+		"memory": newMemoryState,
+		"sqlite": newSQLiteState,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newState(t)
+			now := time.Now()
+
+			user := createTestUser(t, s, "alice")
+			feeds := map[string]database.Feed{}
+			for _, feedName := range []string{"new", "late", "later", "future"} {
+				feeds[feedName] = createTestFeed(t, s, user, "https://example.com/"+feedName+".xml")
+			}
+			postpone := func(feedName string, at time.Time) {
+				t.Helper()
+				err := s.store.PostponeFeedFetch(ctx, database.PostponeFeedFetchParams{
+					ID:          feeds[feedName].ID,
+					NextFetchAt: sql.NullTime{Time: at, Valid: true},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			postpone("late", now.Add(-time.Hour))
+			postpone("later", now.Add(-3*time.Hour))
+			postpone("future", now.Add(time.Hour))
+
+			// Feeds never scheduled come first, then the one due the longest.
+			for _, want := range []string{"new", "later", "late"} {
+				feed, err := s.store.GetNextFeedToFetch(ctx, sql.NullTime{Time: now, Valid: true})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if feed.ID != feeds[want].ID {
+					t.Fatalf("GetNextFeedToFetch() = %s, want %s", feed.Url, feeds[want].Url)
+				}
+				postpone(want, now.Add(2*time.Hour))
+			}
+
+			_, err := s.store.GetNextFeedToFetch(ctx, sql.NullTime{Time: now, Valid: true})
+			if err != sql.ErrNoRows {
+				t.Errorf("GetNextFeedToFetch() error = %v with no feed due, want sql.ErrNoRows", err)
+			}
+		})
+	}
+}
+
+// createPublishedPosts adds a post to feed published at each of the given
+// times, first seen now. A zero time stands for an undated post, which
+// publishedTime dates when it is first seen.
+func createPublishedPosts(t *testing.T, s *state, feed database.Feed, published ...time.Time) {
+	t.Helper()
+
+	for _, at := range published {
+		firstSeen := time.Now()
+		if at.IsZero() {
+			at = firstSeen
+		}
+		_, err := s.store.CreatePost(context.Background(), database.CreatePostParams{
+			ID:          uuid.New(),
+			CreatedAt:   firstSeen,
+			UpdatedAt:   firstSeen,
+			Title:       "Post",
+			Url:         feed.Url + "#" + uuid.NewString(),
+			PublishedAt: sql.NullTime{Time: at, Valid: true},
+			FeedID:      feed.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// every returns n times spaced by gap, the newest at newest.
+func every(n int, gap time.Duration, newest time.Time) []time.Time {
+	var times []time.Time
+	for i := range n {
+		times = append(times, newest.Add(-time.Duration(i)*gap))
+	}
+	return times
+}
+
+func TestPollInterval(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		override  time.Duration
+		published []time.Time
+		undated   int
+		want      time.Duration
+	}{
+		{"override wins", 2 * time.Hour, every(5, time.Minute, now), 0, 2 * time.Hour},
+		{"no posts", 0, nil, 0, 6 * time.Hour},
+		{"one dated post", 0, every(1, 0, now), 3, 6 * time.Hour},
+		{"half the gap", 0, every(5, 2*time.Hour, now), 0, time.Hour},
+		{"clamped to min", 0, every(5, 2*time.Minute, now), 0, 10 * time.Minute},
+		{"clamped to max", 0, every(5, 48*time.Hour, now), 0, 6 * time.Hour},
+		{"dormant feed waits half its silence", 0, every(5, time.Hour, now.Add(-4*time.Hour)), 0, 2 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newMemoryState(t)
+			s.cfg.MinFetchInterval = "10m"
+			s.cfg.MaxFetchInterval = "6h"
+
+			feed := createTestFeed(t, s, createTestUser(t, s, "alice"), "https://example.com/feed.xml")
+			createPublishedPosts(t, s, feed, tt.published...)
+			for range tt.undated {
+				createPublishedPosts(t, s, feed, time.Time{})
+			}
+			if tt.override > 0 {
+				feed.FetchIntervalSeconds = sql.NullInt32{Int32: int32(tt.override / time.Second), Valid: true}
+			}
+
+			got, err := pollInterval(context.Background(), s, feed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The time since the newest post grows while the test runs.
+			if got < tt.want || got > tt.want+time.Minute {
+				t.Errorf("pollInterval() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUntilNextFetch(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		feeds     int
+		nextFetch time.Duration
+		want      time.Duration
+	}{
+		{"no feeds wakes at min", 0, 0, 10 * time.Minute},
+		{"unscheduled feed is due", 1, 0, 0},
+		{"overdue feed is due", 1, -time.Minute, 0},
+		{"soon", 1, 5 * time.Minute, 5 * time.Minute},
+		{"capped at min", 1, 3 * time.Hour, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newMemoryState(t)
+			s.cfg.MinFetchInterval = "10m"
+
+			if tt.feeds > 0 {
+				feed := createTestFeed(t, s, createTestUser(t, s, "alice"), "https://example.com/feed.xml")
+				if tt.nextFetch != 0 {
+					err := s.store.PostponeFeedFetch(ctx, database.PostponeFeedFetchParams{
+						ID:          feed.ID,
+						NextFetchAt: sql.NullTime{Time: time.Now().Add(tt.nextFetch), Valid: true},
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			got, err := untilNextFetch(ctx, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got > tt.want || got < tt.want-time.Minute {
+				t.Errorf("untilNextFetch() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
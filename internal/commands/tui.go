@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/ctiller15/gator/internal/tui"
+)
+
+func handlerTUI(s *state, cmd command, user database.User) error {
+	ctx := context.Background()
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	limit := fs.Int("limit", 200, "maximum number of posts listed per feed or folder")
+	refresh := fs.Duration("refresh", time.Minute, "how often to reload feeds and posts")
+	_, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return err
+	}
+
+	if *limit < 1 {
+		return fmt.Errorf("--limit must be positive")
+	}
+	if *refresh <= 0 {
+		return fmt.Errorf("--refresh must be positive")
+	}
+
+	options := tui.Options{
+		Limit:   *limit,
+		Refresh: *refresh,
+	}
+	return tui.Run(ctx, s.db, user, options)
+}
@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/ctiller15/gator/internal/storage"
+)
+
+// recentActivity is the window "user show" counts recent posts in.
+const recentActivity = 7 * 24 * time.Hour
+
+func handlerUser(s *state, cmd command) error {
+	if len(cmd.args) == 0 {
+		return fmt.Errorf("usage: user delete|rename|show")
+	}
+
+	args := cmd.args[1:]
+	switch cmd.args[0] {
+	case "delete":
+		return handlerUserDelete(s, args)
+	case "rename":
+		return handlerUserRename(s, args)
+	case "show":
+		return handlerUserShow(s, args)
+	default:
+		return fmt.Errorf("unknown user command %s", cmd.args[0])
+	}
+}
+
+func handlerUserDelete(s *state, args []string) error {
+	ctx := context.Background()
+
+	fs := flag.NewFlagSet("user delete", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) < 1 {
+		return fmt.Errorf("must provide a username")
+	}
+
+	user, err := s.store.GetUser(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("no user named %s", args[0])
+	}
+	err = checkUserAccess(s, user.Name)
+	if err != nil {
+		return err
+	}
+
+	// The last user may go, the next one to register becomes an admin, but
+	// the last admin of several users would lock the others out.
+	users, err := s.store.GetUsers(ctx)
+	if err != nil {
+		return err
+	}
+	if len(users) > 1 {
+		err = checkNotLastAdmin(ctx, s, user)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !*yes {
+		stats, err := s.store.GetUserStats(ctx, database.GetUserStatsParams{UserID: user.ID, Since: time.Now()})
+		if err != nil {
+			return err
+		}
+
+		question := fmt.Sprintf("this deletes %s with %d follows, %d folders, %d filter rules and %d webhooks, continue?", user.Name, stats.Follows, stats.Folders, stats.FilterRules, stats.Webhooks)
+		ok, err := confirm(question)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("delete cancelled")
+			return nil
+		}
+	}
+
+	// Follows, folders, filter rules and webhooks are removed by the
+	// database's cascading deletes.
+	_, err = s.store.DeleteUserByName(ctx, user.Name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("user %s has been deleted\n", user.Name)
+
+	if user.Name == s.cfg.CurrentUserName {
+		err = s.cfg.SetUser("")
+		if err != nil {
+			return err
+		}
+		fmt.Println("no user is logged in now, use login or register")
+	}
+
+	return nil
+}
+
+func handlerUserRename(s *state, args []string) error {
+	ctx := context.Background()
+
+	if len(args) < 2 {
+		return fmt.Errorf("must provide the old and the new username")
+	}
+
+	oldName := args[0]
+	newName := args[1]
+	err := checkUserAccess(s, oldName)
+	if err != nil {
+		return err
+	}
+
+	renameUserParams := database.RenameUserParams{
+		NewName: newName,
+		OldName: oldName,
+	}
+	updated, err := s.store.RenameUser(ctx, renameUserParams)
+	if storage.IsDuplicate(err) {
+		return fmt.Errorf("user %s already exists", newName)
+	}
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return fmt.Errorf("no user named %s", oldName)
+	}
+
+	fmt.Printf("user %s has been renamed to %s\n", oldName, newName)
+
+	if oldName == s.cfg.CurrentUserName {
+		err = s.cfg.SetUser(newName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkUserAccess lets the current user change their own account, and
+// admins change anyone's.
+func checkUserAccess(s *state, name string) error {
+	if name == s.cfg.CurrentUserName {
+		return nil
+	}
+
+	current, err := s.store.GetUser(context.Background(), s.cfg.CurrentUserName)
+	if err != nil || current.Role != roleAdmin {
+		return fmt.Errorf("only admins can change other users")
+	}
+
+	return nil
+}
+
+func handlerUserShow(s *state, args []string) error {
+	ctx := context.Background()
+
+	name := s.cfg.CurrentUserName
+	if len(args) > 0 {
+		name = args[0]
+	}
+	if name == "" {
+		return fmt.Errorf("must provide a username")
+	}
+
+	user, err := s.store.GetUser(ctx, name)
+	if err != nil {
+		return fmt.Errorf("no user named %s", name)
+	}
+
+	getUserStatsParams := database.GetUserStatsParams{
+		UserID: user.ID,
+		Since:  time.Now().Add(-recentActivity),
+	}
+	stats, err := s.store.GetUserStats(ctx, getUserStatsParams)
+	if err != nil {
+		return err
+	}
+
+	current := ""
+	if user.Name == s.cfg.CurrentUserName {
+		current = " (current)"
+	}
+	email := "not set"
+	if user.Email.Valid {
+		email = user.Email.String
+	}
+	lastDigest := "never"
+	if user.LastDigestAt.Valid {
+		lastDigest = user.LastDigestAt.Time.Local().Format(time.DateTime)
+	}
+
+	fmt.Printf("%s%s\n", user.Name, current)
+	fmt.Printf("  id:           %s\n", user.ID)
+	fmt.Printf("  role:         %s\n", user.Role)
+	fmt.Printf("  registered:   %s\n", user.CreatedAt.Local().Format(time.DateTime))
+	fmt.Printf("  email:        %s\n", email)
+	fmt.Printf("  last digest:  %s\n", lastDigest)
+	fmt.Printf("  follows:      %d feeds, %d folders\n", stats.Follows, stats.Folders)
+	fmt.Printf("  filters:      %d\n", stats.FilterRules)
+	fmt.Printf("  webhooks:     %d\n", stats.Webhooks)
+	fmt.Printf("  posts:        %d, %d collected in the last 7 days\n", stats.Posts, stats.RecentPosts)
+	return nil
+}
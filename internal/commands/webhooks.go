@@ -0,0 +1,401 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/ctiller15/gator/internal/notify"
+	"github.com/google/uuid"
+)
+
+var webhookFormats = []string{notify.FormatJSON, notify.FormatSlack, notify.FormatDiscord}
+
+// webhookWorkers is the number of webhook notifications agg sends at once.
+const webhookWorkers = 4
+
+// webhookSender delivers notifications in the background, so that a slow or
+// dead endpoint retrying with backoff does not hold up fetching. Once all
+// webhookWorkers are busy, Go waits for one to finish.
+type webhookSender struct {
+	notifier *notify.Notifier
+	slots    chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newWebhookSender(workers int) *webhookSender {
+	return &webhookSender{
+		notifier: notify.New(),
+		slots:    make(chan struct{}, workers),
+	}
+}
+
+// Go runs deliver in the background once a worker is free.
+func (w *webhookSender) Go(deliver func()) {
+	w.slots <- struct{}{}
+	w.wg.Add(1)
+
+	go func() {
+		defer w.wg.Done()
+		defer func() { <-w.slots }()
+
+		deliver()
+	}()
+}
+
+// Wait blocks until every delivery started with Go has finished.
+func (w *webhookSender) Wait() {
+	w.wg.Wait()
+}
+
+func handlerWebhook(s *state, cmd command, user database.User) error {
+	if len(cmd.args) == 0 {
+		return fmt.Errorf("usage: webhook add|list|rm|test|log")
+	}
+
+	args := cmd.args[1:]
+	switch cmd.args[0] {
+	case "add":
+		return handlerWebhookAdd(s, args, user)
+	case "list":
+		return handlerWebhookList(s, user)
+	case "rm":
+		return handlerWebhookRemove(s, args, user)
+	case "test":
+		return handlerWebhookTest(s, args, user)
+	case "log":
+		return handlerWebhookLog(s, args, user)
+	default:
+		return fmt.Errorf("unknown webhook command %s", cmd.args[0])
+	}
+}
+
+func handlerWebhookAdd(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	fs := flag.NewFlagSet("webhook add", flag.ContinueOnError)
+	feedUrl := fs.String("feed", "", "only notify about posts of the feed with this url")
+	keyword := fs.String("keyword", "", "only notify about posts mentioning this keyword")
+	secret := fs.String("secret", "", "secret used to sign payloads")
+	format := fs.String("format", notify.FormatJSON, "payload format: json, slack or discord")
+	batch := fs.Bool("batch", false, "send one message per scrape instead of one per post")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("must provide a webhook url")
+	}
+
+	if !slices.Contains(webhookFormats, *format) {
+		return fmt.Errorf("format must be one of %s", strings.Join(webhookFormats, ", "))
+	}
+
+	feedID := uuid.NullUUID{}
+	if *feedUrl != "" {
+		feed, err := s.store.GetFeedByUrl(ctx, *feedUrl)
+		if err != nil {
+			return fmt.Errorf("feed %s not found: %w", *feedUrl, err)
+		}
+
+		feedID = uuid.NullUUID{
+			UUID:  feed.ID,
+			Valid: true,
+		}
+	}
+
+	currentTime := time.Now()
+	createWebhookParams := database.CreateWebhookParams{
+		ID:        uuid.New(),
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+		UserID:    user.ID,
+		Url:       args[0],
+		Secret: sql.NullString{
+			String: *secret,
+			Valid:  *secret != "",
+		},
+		Format: *format,
+		Batch:  *batch,
+		FeedID: feedID,
+		Keyword: sql.NullString{
+			String: *keyword,
+			Valid:  *keyword != "",
+		},
+	}
+	webhook, err := s.db.CreateWebhook(ctx, createWebhookParams)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("webhook %s has been added\n", shortID(webhook.ID))
+	return nil
+}
+
+func handlerWebhookList(s *state, user database.User) error {
+	ctx := context.Background()
+
+	webhooks, err := s.db.GetWebhooksForUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		var options []string
+		options = append(options, webhook.Format)
+		if webhook.Batch {
+			options = append(options, "batched")
+		}
+		if webhook.Secret.Valid {
+			options = append(options, "signed")
+		}
+		if webhook.FeedUrl.Valid {
+			options = append(options, "feed "+webhook.FeedUrl.String)
+		}
+		if webhook.Keyword.Valid {
+			options = append(options, fmt.Sprintf("keyword %q", webhook.Keyword.String))
+		}
+
+		fmt.Printf("* %s: %s (%s)\n", shortID(webhook.ID), webhook.Url, strings.Join(options, ", "))
+	}
+
+	return nil
+}
+
+func handlerWebhookRemove(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	webhook, err := findWebhook(ctx, s, args, user)
+	if err != nil {
+		return err
+	}
+
+	deleteWebhookParams := database.DeleteWebhookParams{
+		ID:     webhook.ID,
+		UserID: user.ID,
+	}
+	_, err = s.db.DeleteWebhook(ctx, deleteWebhookParams)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("webhook %s has been removed\n", shortID(webhook.ID))
+	return nil
+}
+
+// handlerWebhookTest sends a sample post to a webhook so receivers can be
+// checked without waiting for agg to find new posts.
+func handlerWebhookTest(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	webhook, err := findWebhook(ctx, s, args, user)
+	if err != nil {
+		return err
+	}
+
+	publishedAt := time.Now()
+	samplePost := notify.Post{
+		Title:       "gator webhook test",
+		URL:         "https://example.com/gator-webhook-test",
+		Description: "This is a test notification sent by gator.",
+		PublishedAt: &publishedAt,
+		FeedName:    "gator",
+		FeedURL:     "https://example.com/feed.xml",
+	}
+
+	webhookRow := database.Webhook{
+		ID:     webhook.ID,
+		Url:    webhook.Url,
+		Secret: webhook.Secret,
+		Format: webhook.Format,
+		Batch:  webhook.Batch,
+	}
+	delivered := deliverWebhook(ctx, s, notify.New(), webhookRow, []notify.Post{samplePost})
+	if !delivered {
+		return fmt.Errorf("test delivery to %s failed, see webhook log", webhook.Url)
+	}
+
+	fmt.Printf("test delivery to %s succeeded\n", webhook.Url)
+	return nil
+}
+
+func handlerWebhookLog(s *state, args []string, user database.User) error {
+	ctx := context.Background()
+
+	deliveryLimit := 20
+	if len(args) > 0 {
+		limit, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+
+		deliveryLimit = limit
+	}
+
+	getDeliveriesParams := database.GetWebhookDeliveriesForUserParams{
+		UserID: user.ID,
+		Limit:  int32(deliveryLimit),
+	}
+	deliveries, err := s.db.GetWebhookDeliveriesForUser(ctx, getDeliveriesParams)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		status := "delivered"
+		if !delivery.Delivered {
+			status = "failed: " + delivery.Error.String
+		}
+
+		fmt.Printf("* %s %s: %d post(s), %d attempt(s), %s\n", delivery.CreatedAt.Format(time.DateTime), delivery.WebhookUrl, delivery.PostCount, delivery.Attempts, status)
+	}
+
+	return nil
+}
+
+func findWebhook(ctx context.Context, s *state, args []string, user database.User) (database.GetWebhooksForUserRow, error) {
+	if len(args) == 0 {
+		return database.GetWebhooksForUserRow{}, fmt.Errorf("must provide a webhook id")
+	}
+
+	webhooks, err := s.db.GetWebhooksForUser(ctx, user.ID)
+	if err != nil {
+		return database.GetWebhooksForUserRow{}, err
+	}
+
+	var matches []database.GetWebhooksForUserRow
+	for _, webhook := range webhooks {
+		if strings.HasPrefix(webhook.ID.String(), strings.ToLower(args[0])) {
+			matches = append(matches, webhook)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return database.GetWebhooksForUserRow{}, fmt.Errorf("webhook %s not found", args[0])
+	case 1:
+		return matches[0], nil
+	default:
+		return database.GetWebhooksForUserRow{}, fmt.Errorf("webhook id %s is ambiguous", args[0])
+	}
+}
+
+// notifyWebhooks hands newly inserted posts to sender for the webhooks of
+// every user following the feed. Failed deliveries are recorded in the
+// delivery log and do not interrupt scraping.
+func notifyWebhooks(ctx context.Context, s *state, sender *webhookSender, feed database.Feed, posts []database.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	webhooks, err := s.db.GetWebhooksForFeed(ctx, feed.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		var matching []notify.Post
+		for _, post := range posts {
+			if webhook.Keyword.Valid && !mentions(post, webhook.Keyword.String) {
+				continue
+			}
+			matching = append(matching, notificationPost(feed, post))
+		}
+
+		if len(matching) > 0 {
+			sender.Go(func() {
+				deliverWebhook(ctx, s, sender.notifier, webhook, matching)
+			})
+		}
+	}
+
+	return nil
+}
+
+// deliverWebhook sends posts to a webhook and records every message in the
+// delivery log. It reports whether all messages were delivered.
+func deliverWebhook(ctx context.Context, s *state, notifier *notify.Notifier, webhook database.Webhook, posts []notify.Post) bool {
+	messages, err := notify.Messages(webhook.Format, webhook.Batch, posts)
+	if err != nil {
+		fmt.Printf("could not build payload for webhook %s: %v\n", shortID(webhook.ID), err)
+		return false
+	}
+
+	allDelivered := true
+
+	for _, message := range messages {
+		delivery := notifier.Deliver(ctx, webhook.Url, webhook.Secret.String, message.Body)
+		allDelivered = allDelivered && delivery.Delivered()
+
+		postID := uuid.NullUUID{}
+		if len(message.Posts) == 1 {
+			parsedID, err := uuid.Parse(message.Posts[0].ID)
+			postID = uuid.NullUUID{
+				UUID:  parsedID,
+				Valid: err == nil,
+			}
+		}
+
+		errorMessage := sql.NullString{}
+		if delivery.Err != nil {
+			errorMessage = sql.NullString{
+				String: delivery.Err.Error(),
+				Valid:  true,
+			}
+		}
+
+		createDeliveryParams := database.CreateWebhookDeliveryParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			WebhookID: webhook.ID,
+			PostID:    postID,
+			PostCount: int32(len(message.Posts)),
+			Attempts:  int32(delivery.Attempts),
+			StatusCode: sql.NullInt32{
+				Int32: int32(delivery.StatusCode),
+				Valid: delivery.StatusCode != 0,
+			},
+			Error:     errorMessage,
+			Delivered: delivery.Delivered(),
+		}
+		err := s.db.CreateWebhookDelivery(ctx, createDeliveryParams)
+		if err != nil {
+			fmt.Printf("could not record delivery to webhook %s: %v\n", shortID(webhook.ID), err)
+		}
+	}
+
+	return allDelivered
+}
+
+func notificationPost(feed database.Feed, post database.Post) notify.Post {
+	notification := notify.Post{
+		ID:          post.ID.String(),
+		Title:       post.Title,
+		URL:         post.Url,
+		Description: post.Description.String,
+		Author:      post.Author.String,
+		FeedName:    feed.Name,
+		FeedURL:     feed.Url,
+	}
+
+	if post.PublishedAt.Valid {
+		publishedAt := post.PublishedAt.Time
+		notification.PublishedAt = &publishedAt
+	}
+
+	return notification
+}
+
+func mentions(post database.Post, keyword string) bool {
+	keyword = strings.ToLower(keyword)
+	return strings.Contains(strings.ToLower(post.Title), keyword) ||
+		strings.Contains(strings.ToLower(post.Description.String), keyword)
+}
@@ -0,0 +1,249 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctiller15/gator/internal/config"
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/ctiller15/gator/internal/migrate"
+	"github.com/ctiller15/gator/internal/notify"
+	"github.com/ctiller15/gator/internal/storage"
+	"github.com/google/uuid"
+)
+
+// newSQLiteState returns a state backed by a migrated in-memory SQLite
+// database, for code that needs more than the Store.
+func newSQLiteState(t *testing.T) *state {
+	t.Helper()
+
+	db, err := storage.Open("sqlite::memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	provider, err := migrate.NewProvider(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = provider.Up(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewState(&config.Config{}, db.Queries(), db)
+}
+
+// webhookReceiver counts the requests it gets and answers with status.
+type webhookReceiver struct {
+	mu        sync.Mutex
+	status    int
+	delay     time.Duration
+	requests  int
+	signature string
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	time.Sleep(r.delay)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests++
+	r.signature = req.Header.Get("X-Gator-Signature")
+	w.WriteHeader(r.status)
+}
+
+func TestNotifyWebhooksRecordsDeliveries(t *testing.T) {
+	ctx := context.Background()
+	s := newSQLiteState(t)
+
+	good := &webhookReceiver{status: http.StatusOK}
+	goodServer := httptest.NewServer(good)
+	defer goodServer.Close()
+
+	bad := &webhookReceiver{status: http.StatusInternalServerError}
+	badServer := httptest.NewServer(bad)
+	defer badServer.Close()
+
+	user := createTestUser(t, s, "alice")
+	feed := createTestFeed(t, s, user, "https://example.com/feed.xml")
+	first := createTestPost(t, s, feed, "Go 1.24 released")
+	second := createTestPost(t, s, feed, "Gardening tips")
+
+	slackHook := createTestWebhook(t, s, database.CreateWebhookParams{
+		UserID: user.ID,
+		Url:    goodServer.URL,
+		Secret: sql.NullString{String: "s3cret", Valid: true},
+		Format: notify.FormatSlack,
+		Batch:  true,
+	})
+	jsonHook := createTestWebhook(t, s, database.CreateWebhookParams{
+		UserID:  user.ID,
+		Url:     badServer.URL,
+		Format:  notify.FormatJSON,
+		Keyword: sql.NullString{String: "go", Valid: true},
+	})
+
+	sender := newWebhookSender(webhookWorkers)
+	sender.notifier.Backoff = time.Millisecond
+
+	err := notifyWebhooks(ctx, s, sender, feed, []database.Post{first, second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender.Wait()
+
+	if good.requests != 1 || good.signature == "" {
+		t.Errorf("slack receiver got %d requests with signature %q, want one signed batch", good.requests, good.signature)
+	}
+	if bad.requests != 3 {
+		t.Errorf("failing receiver got %d requests, want 3 attempts", bad.requests)
+	}
+
+	deliveries, err := s.db.GetWebhookDeliveriesForUser(ctx, database.GetWebhookDeliveriesForUserParams{UserID: user.ID, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("got %d delivery log rows, want 2", len(deliveries))
+	}
+
+	for _, delivery := range deliveries {
+		switch delivery.WebhookID {
+		case slackHook.ID:
+			if !delivery.Delivered || delivery.Attempts != 1 || delivery.StatusCode.Int32 != http.StatusOK || delivery.PostCount != 2 || delivery.PostID.Valid {
+				t.Errorf("slack delivery = %+v, want one delivered batch of 2 posts", delivery)
+			}
+		case jsonHook.ID:
+			// Only the post mentioning the keyword is sent, on its own.
+			if delivery.Delivered || delivery.Attempts != 3 || delivery.StatusCode.Int32 != http.StatusInternalServerError || !delivery.Error.Valid {
+				t.Errorf("json delivery = %+v, want 3 failed attempts", delivery)
+			}
+			if delivery.PostCount != 1 || delivery.PostID.UUID != first.ID {
+				t.Errorf("json delivery announced %d posts (%s), want only %s", delivery.PostCount, delivery.PostID.UUID, first.ID)
+			}
+		default:
+			t.Errorf("delivery for unknown webhook %s", delivery.WebhookID)
+		}
+	}
+}
+
+func TestNotifyWebhooksDoesNotWaitForDelivery(t *testing.T) {
+	ctx := context.Background()
+	s := newSQLiteState(t)
+
+	slow := &webhookReceiver{status: http.StatusOK, delay: 500 * time.Millisecond}
+	server := httptest.NewServer(slow)
+	defer server.Close()
+
+	user := createTestUser(t, s, "alice")
+	feed := createTestFeed(t, s, user, "https://example.com/feed.xml")
+	post := createTestPost(t, s, feed, "A post")
+	createTestWebhook(t, s, database.CreateWebhookParams{
+		UserID: user.ID,
+		Url:    server.URL,
+		Format: notify.FormatJSON,
+	})
+
+	sender := newWebhookSender(webhookWorkers)
+
+	start := time.Now()
+	err := notifyWebhooks(ctx, s, sender, feed, []database.Post{post})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= slow.delay {
+		t.Errorf("notifyWebhooks took %s, it should not wait for the receiver", elapsed)
+	}
+
+	sender.Wait()
+	if slow.requests != 1 {
+		t.Errorf("receiver got %d requests after Wait, want 1", slow.requests)
+	}
+}
+
+func createTestUser(t *testing.T, s *state, name string) database.User {
+	t.Helper()
+
+	user, err := s.store.CreateUser(context.Background(), database.CreateUserParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Name:      name,
+		Role:      roleMember,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return user
+}
+
+func createTestFeed(t *testing.T, s *state, user database.User, url string) database.Feed {
+	t.Helper()
+
+	feed, err := s.store.CreateFeed(context.Background(), database.CreateFeedParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Name:      url,
+		Url:       url,
+		AddedBy:   uuid.NullUUID{UUID: user.ID, Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.store.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserID:    user.ID,
+		FeedID:    feed.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return feed
+}
+
+func createTestPost(t *testing.T, s *state, feed database.Feed, title string) database.Post {
+	t.Helper()
+
+	post, err := s.store.CreatePost(context.Background(), database.CreatePostParams{
+		ID:          uuid.New(),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		Title:       title,
+		Url:         feed.Url + "#" + uuid.NewString(),
+		PublishedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		FeedID:      feed.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return post
+}
+
+func createTestWebhook(t *testing.T, s *state, params database.CreateWebhookParams) database.Webhook {
+	t.Helper()
+
+	params.ID = uuid.New()
+	params.CreatedAt = time.Now()
+	params.UpdatedAt = time.Now()
+	webhook, err := s.db.CreateWebhook(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return webhook
+}
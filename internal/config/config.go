@@ -1,31 +1,136 @@
 package config
 
 import (
-	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	DB_URL          string `json:"db_url"`
-	CurrentUserName string `json:"current_user_name"`
+	DB_URL              string `json:"db_url"`
+	CurrentUserName     string `json:"current_user_name"`
+	DownloadDir         string `json:"download_dir,omitempty"`
+	DownloadConcurrency int    `json:"download_concurrency,omitempty"`
+	DownloadQuotaMB     int64  `json:"download_quota_mb,omitempty"`
+	SMTPHost            string `json:"smtp_host,omitempty"`
+	SMTPPort            int    `json:"smtp_port,omitempty"`
+	SMTPUsername        string `json:"smtp_username,omitempty"`
+	SMTPPassword        string `json:"smtp_password,omitempty"`
+	SMTPFrom            string `json:"smtp_from,omitempty"`
+	DigestTemplateDir   string `json:"digest_template_dir,omitempty"`
+	BackupDir           string `json:"backup_dir,omitempty"`
+	MinFetchInterval    string `json:"min_fetch_interval,omitempty"`
+	MaxFetchInterval    string `json:"max_fetch_interval,omitempty"`
+	HostRequestsPerMin  int    `json:"host_requests_per_minute,omitempty"`
+	HostMaxConnections  int    `json:"host_max_connections,omitempty"`
+	HostLimitOverrides  string `json:"host_limits,omitempty"`
+	CheckRobotsTxt      bool   `json:"check_robots_txt,omitempty"`
+	ConnectTimeout      string `json:"connect_timeout,omitempty"`
+	FetchTimeout        string `json:"fetch_timeout,omitempty"`
+	MaxFeedSizeMB       int64  `json:"max_feed_size_mb,omitempty"`
+	MaxRedirects        int    `json:"max_redirects,omitempty"`
+	ProxyURL            string `json:"proxy_url,omitempty"`
+	ContactURL          string `json:"contact_url,omitempty"`
+
+	path          string
+	profile       string
+	profileExists bool
+}
+
+const (
+	defaultDownloadConcurrency = 2
+	defaultSMTPPort            = 587
+	defaultMinFetchInterval    = 15 * time.Minute
+	defaultMaxFetchInterval    = 24 * time.Hour
+	defaultHostRequestsPerMin  = 30
+	defaultHostMaxConnections  = 2
+	defaultConnectTimeout      = 10 * time.Second
+	defaultFetchTimeout        = time.Minute
+	defaultMaxFeedSizeMB       = 10
+	defaultMaxRedirects        = 5
+)
+
+// HostLimit is the request rate and number of simultaneous connections
+// allowed to one host.
+type HostLimit struct {
+	RequestsPerMinute int
+	MaxConnections    int
 }
 
-func Read() (Config, error) {
-	configFilePath, err := getConfigFilePath()
+// Options selects the config file and profile to read. Empty fields fall
+// back to the GATOR_CONFIG and GATOR_PROFILE environment variables and then
+// to the defaults described on Read.
+type Options struct {
+	Path    string
+	Profile string
+}
 
+// Read loads the config. The file is the first of Options.Path,
+// $GATOR_CONFIG, $XDG_CONFIG_HOME/gator/config.json and the legacy
+// ~/.gatorconfig.json that exists; a missing file yields an empty config that
+// is created at the XDG location on the first write.
+//
+// Settings of the selected profile (Options.Profile, $GATOR_PROFILE or the
+// file's "profile" key) are layered over the top-level settings, and
+// GATOR_<SETTING> environment variables such as GATOR_DB_URL override both.
+func Read(options Options) (Config, error) {
+	path := options.Path
+	if path == "" {
+		path = os.Getenv("GATOR_CONFIG")
+	}
+	if path == "" {
+		var err error
+		path, err = defaultConfigFilePath()
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	doc, err := readDocument(path)
 	if err != nil {
 		return Config{}, err
 	}
 
-	data, err := os.ReadFile(configFilePath)
+	profile := options.Profile
+	if profile == "" {
+		profile = os.Getenv("GATOR_PROFILE")
+	}
+	if profile == "" {
+		profile, err = documentString(doc, profileKey)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
 
+	config := Config{
+		path:    path,
+		profile: profile,
+	}
+
+	err = decodeSettings(doc, &config)
 	if err != nil {
-		return Config{}, err
+		return Config{}, fmt.Errorf("%s: %w", path, err)
 	}
 
-	var config Config
+	if profile != "" {
+		profiles, err := documentProfiles(doc)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", path, err)
+		}
+
+		settings, ok := profiles[profile]
+		config.profileExists = ok
+
+		err = decodeSettings(settings, &config)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: profile %q: %w", path, profile, err)
+		}
+	}
 
-	err = json.Unmarshal(data, &config)
+	err = config.applyEnvironment()
 	if err != nil {
 		return Config{}, err
 	}
@@ -33,45 +138,219 @@ func Read() (Config, error) {
 	return config, nil
 }
 
+// Path returns the config file the settings were read from and are written
+// to.
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Profile returns the name of the selected profile, or "" for the top-level
+// settings.
+func (c *Config) Profile() string {
+	return c.profile
+}
+
+// ProfileExists reports whether the selected profile is defined in the
+// config file. A missing profile holds the top-level settings and is
+// created by the first Set.
+func (c *Config) ProfileExists() bool {
+	return c.profile == "" || c.profileExists
+}
+
 func (c *Config) SetUser(username string) error {
-	c.CurrentUserName = username
+	return c.Set("current_user_name", username)
+}
 
-	err := write(*c)
+// DownloadDirectory returns the directory enclosures are downloaded to,
+// defaulting to ~/gator/downloads.
+func (c *Config) DownloadDirectory() (string, error) {
+	if c.DownloadDir != "" {
+		return c.DownloadDir, nil
+	}
 
+	homedir, err := os.UserHomeDir()
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return filepath.Join(homedir, "gator", "downloads"), nil
 }
 
-func write(cfg Config) error {
-	configFilePath, err := getConfigFilePath()
+// BackupDirectory returns the directory automatic backups are saved to,
+// defaulting to ~/gator/backups.
+func (c *Config) BackupDirectory() (string, error) {
+	if c.BackupDir != "" {
+		return c.BackupDir, nil
+	}
 
+	homedir, err := os.UserHomeDir()
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	bytes, err := json.MarshalIndent(cfg, "", "  ")
+	return filepath.Join(homedir, "gator", "backups"), nil
+}
+
+// DownloadWorkers returns the number of enclosures downloaded in parallel.
+func (c *Config) DownloadWorkers() int {
+	if c.DownloadConcurrency > 0 {
+		return c.DownloadConcurrency
+	}
+
+	return defaultDownloadConcurrency
+}
+
+// DownloadQuotaBytes returns the disk quota for downloaded enclosures, or
+// zero when downloads are not limited.
+func (c *Config) DownloadQuotaBytes() int64 {
+	return c.DownloadQuotaMB * 1024 * 1024
+}
+
+// FetchIntervals returns the shortest and longest time agg waits between
+// two fetches of a feed, defaulting to 15 minutes and a day.
+func (c *Config) FetchIntervals() (time.Duration, time.Duration, error) {
+	minInterval, err := parseInterval("min_fetch_interval", c.MinFetchInterval, defaultMinFetchInterval)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 
-	err = os.WriteFile(configFilePath, bytes, 0666)
+	maxInterval, err := parseInterval("max_fetch_interval", c.MaxFetchInterval, defaultMaxFetchInterval)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if minInterval > maxInterval {
+		return 0, 0, fmt.Errorf("min_fetch_interval %s is longer than max_fetch_interval %s", minInterval, maxInterval)
+	}
 
+	return minInterval, maxInterval, nil
+}
+
+// HostLimits returns the limit applied to every host, 30 requests a minute
+// over at most 2 connections unless set, and the limits of the hosts listed
+// in host_limits. host_limits is a comma separated list of
+// host=requests_per_minute[/max_connections] entries such as
+// "substack.com=10/1,medium.com=20"; entries without max_connections use
+// the global one.
+func (c *Config) HostLimits() (HostLimit, map[string]HostLimit, error) {
+	defaults := HostLimit{
+		RequestsPerMinute: defaultHostRequestsPerMin,
+		MaxConnections:    defaultHostMaxConnections,
+	}
+	if c.HostRequestsPerMin > 0 {
+		defaults.RequestsPerMinute = c.HostRequestsPerMin
+	}
+	if c.HostMaxConnections > 0 {
+		defaults.MaxConnections = c.HostMaxConnections
+	}
+
+	hosts := make(map[string]HostLimit)
+	for _, entry := range strings.Split(c.HostLimitOverrides, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		host, value, ok := strings.Cut(entry, "=")
+		rate, connections, hasConnections := strings.Cut(value, "/")
+		limit := HostLimit{MaxConnections: defaults.MaxConnections}
+
+		var err error
+		limit.RequestsPerMinute, err = strconv.Atoi(strings.TrimSpace(rate))
+		if hasConnections && err == nil {
+			limit.MaxConnections, err = strconv.Atoi(strings.TrimSpace(connections))
+		}
+		if !ok || strings.TrimSpace(host) == "" || err != nil || limit.RequestsPerMinute < 1 || limit.MaxConnections < 1 {
+			return HostLimit{}, nil, fmt.Errorf("host_limits entry %q must look like host=requests_per_minute[/max_connections]", entry)
+		}
+
+		hosts[strings.TrimSpace(host)] = limit
+	}
+
+	return defaults, hosts, nil
+}
+
+// FetchTimeouts returns how long agg waits to connect to a feed's server,
+// 10 seconds unless set, and for the whole request, a minute unless set.
+func (c *Config) FetchTimeouts() (time.Duration, time.Duration, error) {
+	connectTimeout, err := parseInterval("connect_timeout", c.ConnectTimeout, defaultConnectTimeout)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fetchTimeout, err := parseInterval("fetch_timeout", c.FetchTimeout, defaultFetchTimeout)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 
-	return nil
+	return connectTimeout, fetchTimeout, nil
 }
 
-func getConfigFilePath() (string, error) {
-	homedir, err := os.UserHomeDir()
+// MaxFeedSizeBytes returns the size past which a feed is rejected, 10 MiB
+// unless set.
+func (c *Config) MaxFeedSizeBytes() int64 {
+	if c.MaxFeedSizeMB > 0 {
+		return c.MaxFeedSizeMB * 1024 * 1024
+	}
 
+	return defaultMaxFeedSizeMB * 1024 * 1024
+}
+
+// FetchRedirects returns the number of redirects followed when fetching a
+// feed, 5 unless set.
+func (c *Config) FetchRedirects() int {
+	if c.MaxRedirects > 0 {
+		return c.MaxRedirects
+	}
+
+	return defaultMaxRedirects
+}
+
+func parseInterval(name, value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration such as 30m or 6h", name)
+	}
+
+	return interval, nil
+}
+
+// SMTPServerPort returns the configured SMTP port, defaulting to the
+// submission port.
+func (c *Config) SMTPServerPort() int {
+	if c.SMTPPort > 0 {
+		return c.SMTPPort
+	}
+
+	return defaultSMTPPort
+}
+
+func defaultConfigFilePath() (string, error) {
+	homedir, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
 	}
 
-	return homedir + "/.gatorconfig.json", nil
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		configDir = filepath.Join(homedir, ".config")
+	}
+
+	path := filepath.Join(configDir, "gator", "config.json")
+	_, err = os.Stat(path)
+	if err == nil {
+		return path, nil
+	}
+
+	legacyPath := filepath.Join(homedir, ".gatorconfig.json")
+	_, err = os.Stat(legacyPath)
+	if err == nil {
+		return legacyPath, nil
+	}
+
+	return path, nil
 }
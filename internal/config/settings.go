@@ -0,0 +1,292 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	profileKey  = "profile"
+	profilesKey = "profiles"
+)
+
+// Settings returns the names of all settings, as used in the config file and
+// by Get and Set, in declaration order.
+func Settings() []string {
+	var names []string
+	configType := reflect.TypeOf(Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		name, _, _ := strings.Cut(configType.Field(i).Tag.Get("json"), ",")
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// EnvironmentVariable returns the variable that overrides a setting, such
+// as GATOR_DB_URL for db_url.
+func EnvironmentVariable(name string) string {
+	return "GATOR_" + strings.ToUpper(name)
+}
+
+// Get returns the value of a setting formatted as text.
+func (c *Config) Get(name string) (string, error) {
+	field, err := c.field(name)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprint(field.Interface()), nil
+}
+
+// Set changes a setting and saves it to the selected profile of the config
+// file. An empty value removes the setting from the file.
+func (c *Config) Set(name, value string) error {
+	field, err := c.field(name)
+	if err != nil {
+		return err
+	}
+
+	saved, err := setField(field, name, value)
+	if err != nil {
+		return err
+	}
+
+	return c.save(name, saved)
+}
+
+func (c *Config) field(name string) (reflect.Value, error) {
+	configValue := reflect.ValueOf(c).Elem()
+	for i := 0; i < configValue.NumField(); i++ {
+		tagName, _, _ := strings.Cut(configValue.Type().Field(i).Tag.Get("json"), ",")
+		if name != "" && tagName == name {
+			return configValue.Field(i), nil
+		}
+	}
+
+	return reflect.Value{}, fmt.Errorf("unknown setting %q, expected one of: %s", name, strings.Join(Settings(), ", "))
+}
+
+// setField parses value into field and returns the value to store in the
+// config file, or nil if the setting should be removed.
+func setField(field reflect.Value, name, value string) (any, error) {
+	if value == "" {
+		field.SetZero()
+		return nil, nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+		return value, nil
+	case reflect.Int, reflect.Int64:
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a whole number", name)
+		}
+		field.SetInt(number)
+		return number, nil
+	case reflect.Bool:
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", name)
+		}
+		field.SetBool(enabled)
+		return enabled, nil
+	default:
+		return nil, fmt.Errorf("setting %s has unsupported type %s", name, field.Type())
+	}
+}
+
+func (c *Config) applyEnvironment() error {
+	for _, name := range Settings() {
+		value, ok := os.LookupEnv(EnvironmentVariable(name))
+		if !ok {
+			continue
+		}
+
+		field, err := c.field(name)
+		if err != nil {
+			return err
+		}
+
+		_, err = setField(field, name, value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", EnvironmentVariable(name), err)
+		}
+	}
+
+	return nil
+}
+
+// save writes a single setting to the config file. The file is re-read
+// first so that environment overrides and settings of other profiles are
+// not written back.
+func (c *Config) save(name string, value any) error {
+	doc, err := readDocument(c.path)
+	if err != nil {
+		return err
+	}
+
+	settings := doc
+	if c.profile != "" {
+		profiles, err := documentProfiles(doc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.path, err)
+		}
+
+		settings = profiles[c.profile]
+		if settings == nil {
+			settings = make(map[string]any)
+			profiles[c.profile] = settings
+		}
+		doc[profilesKey] = profiles
+		c.profileExists = true
+	}
+
+	if value == nil {
+		delete(settings, name)
+	} else {
+		settings[name] = value
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(c.path, append(data, '\n'))
+}
+
+// readDocument reads the config file as generic JSON so that it can be
+// rewritten without losing profiles or unknown keys. A missing or empty file
+// is an empty document.
+func readDocument(path string) (map[string]any, error) {
+	doc := make(map[string]any)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return doc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return doc, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err = decoder.Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return doc, nil
+}
+
+// decodeSettings overlays the settings present in doc onto config.
+func decodeSettings(doc map[string]any, config *Config) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, config)
+}
+
+func documentString(doc map[string]any, key string) (string, error) {
+	value, ok := doc[key]
+	if !ok {
+		return "", nil
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%q must be a string", key)
+	}
+
+	return text, nil
+}
+
+func documentProfiles(doc map[string]any) (map[string]map[string]any, error) {
+	profiles := make(map[string]map[string]any)
+
+	value, ok := doc[profilesKey]
+	if !ok {
+		return profiles, nil
+	}
+
+	entries, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%q must be an object", profilesKey)
+	}
+
+	for name, entry := range entries {
+		settings, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("profile %q must be an object", name)
+		}
+		profiles[name] = settings
+	}
+
+	return profiles, nil
+}
+
+// writeFileAtomic replaces path with data readable only by the current
+// user. The data is written to a temporary file in the same directory that
+// is renamed over path, so a crash never leaves a truncated config behind.
+func writeFileAtomic(path string, data []byte) error {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err == nil {
+		path = resolved
+	}
+
+	dir := filepath.Dir(path)
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	err = file.Chmod(0600)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Sync()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
@@ -14,15 +14,16 @@ import (
 )
 
 const createFeed = `-- name: CreateFeed :one
-INSERT INTO feeds (id, created_at, updated_at, name, url)
+INSERT INTO feeds (id, created_at, updated_at, name, url, added_by)
 VALUES (
     $1,
     $2,
     $3,
     $4,
-    $5
+    $5,
+    $6
 )
-RETURNING id, created_at, updated_at, name, url, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, last_fetched_at, is_podcast, auto_download, last_fetch_status, last_fetch_error, last_fetch_new_posts, added_by, next_fetch_at, fetch_interval_seconds
 `
 
 type CreateFeedParams struct {
@@ -31,6 +32,7 @@ type CreateFeedParams struct {
 	UpdatedAt time.Time
 	Name      string
 	Url       string
+	AddedBy   uuid.NullUUID
 }
 
 func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error) {
@@ -40,6 +42,7 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		arg.UpdatedAt,
 		arg.Name,
 		arg.Url,
+		arg.AddedBy,
 	)
 	var i Feed
 	err := row.Scan(
@@ -49,12 +52,20 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Name,
 		&i.Url,
 		&i.LastFetchedAt,
+		&i.IsPodcast,
+		&i.AutoDownload,
+		&i.LastFetchStatus,
+		&i.LastFetchError,
+		&i.LastFetchNewPosts,
+		&i.AddedBy,
+		&i.NextFetchAt,
+		&i.FetchIntervalSeconds,
 	)
 	return i, err
 }
 
 const createFeedFollow = `-- name: CreateFeedFollow :one
-WITH inserted_feed_follow AS (INSERT INTO feed_follows (
+INSERT INTO feed_follows (
     id,
     created_at,
     updated_at,
@@ -68,18 +79,9 @@ VALUES (
     $4,
     $5
 )
-RETURNING  id, created_at, updated_at, user_id, feed_id
-)
-
-SELECT 
-    inserted_feed_follow.id, inserted_feed_follow.created_at, inserted_feed_follow.updated_at, inserted_feed_follow.user_id, inserted_feed_follow.feed_id,
-    feeds.name AS feed_name,
-    users.name AS user_name
-    FROM inserted_feed_follow
-    INNER JOIN users
-    ON users.id = inserted_feed_follow.user_id
-    INNER JOIN feeds
-    ON feeds.id = inserted_feed_follow.feed_id
+RETURNING id, created_at, updated_at, user_id, feed_id, folder_id,
+    (SELECT feeds.name FROM feeds WHERE feeds.id = feed_follows.feed_id) AS feed_name,
+    (SELECT users.name FROM users WHERE users.id = feed_follows.user_id) AS user_name
 `
 
 type CreateFeedFollowParams struct {
@@ -96,6 +98,7 @@ type CreateFeedFollowRow struct {
 	UpdatedAt time.Time
 	UserID    uuid.UUID
 	FeedID    uuid.UUID
+	FolderID  uuid.NullUUID
 	FeedName  string
 	UserName  string
 }
@@ -115,6 +118,7 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 		&i.UpdatedAt,
 		&i.UserID,
 		&i.FeedID,
+		&i.FolderID,
 		&i.FeedName,
 		&i.UserName,
 	)
@@ -122,7 +126,7 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 }
 
 const createPost = `-- name: CreatePost :one
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, author)
 VALUES (
     $1,
     $2,
@@ -131,9 +135,10 @@ VALUES (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9
 )
-RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id
+RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id, author
 `
 
 type CreatePostParams struct {
@@ -145,6 +150,7 @@ type CreatePostParams struct {
 	Description sql.NullString
 	PublishedAt sql.NullTime
 	FeedID      uuid.UUID
+	Author      sql.NullString
 }
 
 func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
@@ -157,6 +163,7 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		arg.Description,
 		arg.PublishedAt,
 		arg.FeedID,
+		arg.Author,
 	)
 	var i Post
 	err := row.Scan(
@@ -168,10 +175,43 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.Description,
 		&i.PublishedAt,
 		&i.FeedID,
+		&i.Author,
 	)
 	return i, err
 }
 
+const createPostCategory = `-- name: CreatePostCategory :exec
+INSERT INTO post_categories (post_id, name)
+VALUES (
+    $1,
+    $2
+)
+ON CONFLICT DO NOTHING
+`
+
+type CreatePostCategoryParams struct {
+	PostID uuid.UUID
+	Name   string
+}
+
+func (q *Queries) CreatePostCategory(ctx context.Context, arg CreatePostCategoryParams) error {
+	_, err := q.db.ExecContext(ctx, createPostCategory, arg.PostID, arg.Name)
+	return err
+}
+
+const deleteFeedByUrl = `-- name: DeleteFeedByUrl :execrows
+DELETE FROM feeds
+WHERE url = $1
+`
+
+func (q *Queries) DeleteFeedByUrl(ctx context.Context, url string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFeedByUrl, url)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteFeedFollowByUrl = `-- name: DeleteFeedFollowByUrl :exec
 DELETE FROM feed_follows
 WHERE feed_follows.user_id = $1
@@ -201,6 +241,59 @@ func (q *Queries) DeleteFeeds(ctx context.Context) error {
 	return err
 }
 
+const deletePosts = `-- name: DeletePosts :exec
+DELETE FROM posts
+`
+
+func (q *Queries) DeletePosts(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deletePosts)
+	return err
+}
+
+const getEarliestNextFetch = `-- name: GetEarliestNextFetch :one
+SELECT next_fetch_at
+FROM feeds
+WHERE next_fetch_at IS NOT NULL
+ORDER BY next_fetch_at ASC
+LIMIT 1
+`
+
+func (q *Queries) GetEarliestNextFetch(ctx context.Context) (sql.NullTime, error) {
+	row := q.db.QueryRowContext(ctx, getEarliestNextFetch)
+	var nextFetchAt sql.NullTime
+	err := row.Scan(&nextFetchAt)
+	return nextFetchAt, err
+}
+
+const getFeed = `-- name: GetFeed :one
+SELECT id, created_at, updated_at, name, url, last_fetched_at, is_podcast, auto_download, last_fetch_status, last_fetch_error, last_fetch_new_posts, added_by, next_fetch_at, fetch_interval_seconds
+FROM feeds
+WHERE url = $1
+LIMIT 1
+`
+
+func (q *Queries) GetFeed(ctx context.Context, url string) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeed, url)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.LastFetchedAt,
+		&i.IsPodcast,
+		&i.AutoDownload,
+		&i.LastFetchStatus,
+		&i.LastFetchError,
+		&i.LastFetchNewPosts,
+		&i.AddedBy,
+		&i.NextFetchAt,
+		&i.FetchIntervalSeconds,
+	)
+	return i, err
+}
+
 const getFeedByUrl = `-- name: GetFeedByUrl :one
 SELECT id, feeds.name AS feed_name
 FROM feeds
@@ -221,20 +314,25 @@ func (q *Queries) GetFeedByUrl(ctx context.Context, url string) (GetFeedByUrlRow
 }
 
 const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
-SELECT users.name as user_name, feeds.name as feed_name, users.id as user_id, feeds.id as feed_id
+SELECT users.name as user_name, feeds.name as feed_name, users.id as user_id, feeds.id as feed_id, feeds.url as feed_url, folders.name as folder_name
 FROM users
 INNER JOIN feed_follows
 ON users.id = feed_follows.user_id
 INNER JOIN feeds
 ON feed_follows.feed_id = feeds.id
+LEFT JOIN folders
+ON feed_follows.folder_id = folders.id
 WHERE users.name = $1
+ORDER BY folders.name ASC NULLS FIRST, feeds.name ASC
 `
 
 type GetFeedFollowsForUserRow struct {
-	UserName string
-	FeedName string
-	UserID   uuid.UUID
-	FeedID   uuid.UUID
+	UserName   string
+	FeedName   string
+	UserID     uuid.UUID
+	FeedID     uuid.UUID
+	FeedUrl    string
+	FolderName sql.NullString
 }
 
 func (q *Queries) GetFeedFollowsForUser(ctx context.Context, name string) ([]GetFeedFollowsForUserRow, error) {
@@ -251,6 +349,8 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, name string) ([]Get
 			&i.FeedName,
 			&i.UserID,
 			&i.FeedID,
+			&i.FeedUrl,
+			&i.FolderName,
 		); err != nil {
 			return nil, err
 		}
@@ -265,17 +365,84 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, name string) ([]Get
 	return items, nil
 }
 
+const getFeedPublishTimes = `-- name: GetFeedPublishTimes :many
+SELECT published_at
+FROM posts
+WHERE feed_id = $1
+AND published_at IS NOT NULL
+AND published_at <> created_at
+ORDER BY published_at DESC
+LIMIT $2
+`
+
+type GetFeedPublishTimesParams struct {
+	FeedID uuid.UUID
+	Limit  int32
+}
+
+func (q *Queries) GetFeedPublishTimes(ctx context.Context, arg GetFeedPublishTimesParams) ([]sql.NullTime, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedPublishTimes, arg.FeedID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullTime
+	for rows.Next() {
+		var publishedAt sql.NullTime
+		if err := rows.Scan(&publishedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, publishedAt)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedStats = `-- name: GetFeedStats :one
+SELECT
+    (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.feed_id = $1) AS followers,
+    (SELECT COUNT(*) FROM posts WHERE posts.feed_id = $1) AS posts
+`
+
+type GetFeedStatsRow struct {
+	Followers int64
+	Posts     int64
+}
+
+func (q *Queries) GetFeedStats(ctx context.Context, feedID uuid.UUID) (GetFeedStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedStats, feedID)
+	var i GetFeedStatsRow
+	err := row.Scan(&i.Followers, &i.Posts)
+	return i, err
+}
+
 const getFeeds = `-- name: GetFeeds :many
-SELECT feeds.name AS feed_name, feeds.url, users.name AS user_name
+SELECT feeds.name AS feed_name,
+    feeds.url,
+    feeds.is_podcast,
+    feeds.last_fetched_at,
+    feeds.last_fetch_status,
+    users.name AS added_by_name,
+    (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.feed_id = feeds.id) AS followers
 FROM feeds
-INNER JOIN users
-ON feeds.user_id = users.id
+LEFT JOIN users
+ON feeds.added_by = users.id
+ORDER BY feeds.created_at, feeds.id
 `
 
 type GetFeedsRow struct {
-	FeedName string
-	Url      string
-	UserName string
+	FeedName        string
+	Url             string
+	IsPodcast       bool
+	LastFetchedAt   sql.NullTime
+	LastFetchStatus sql.NullString
+	AddedByName     sql.NullString
+	Followers       int64
 }
 
 func (q *Queries) GetFeeds(ctx context.Context) ([]GetFeedsRow, error) {
@@ -287,7 +454,15 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]GetFeedsRow, error) {
 	var items []GetFeedsRow
 	for rows.Next() {
 		var i GetFeedsRow
-		if err := rows.Scan(&i.FeedName, &i.Url, &i.UserName); err != nil {
+		if err := rows.Scan(
+			&i.FeedName,
+			&i.Url,
+			&i.IsPodcast,
+			&i.LastFetchedAt,
+			&i.LastFetchStatus,
+			&i.AddedByName,
+			&i.Followers,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -302,13 +477,15 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]GetFeedsRow, error) {
 }
 
 const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
-SELECT id, created_at, updated_at, name, url, last_fetched_at
+SELECT id, created_at, updated_at, name, url, last_fetched_at, is_podcast, auto_download, last_fetch_status, last_fetch_error, last_fetch_new_posts, added_by, next_fetch_at, fetch_interval_seconds
 FROM feeds
-ORDER BY last_fetched_at ASC NULLS FIRST
+WHERE next_fetch_at IS NULL OR next_fetch_at <= $1
+ORDER BY next_fetch_at ASC NULLS FIRST
+LIMIT 1
 `
 
-func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, getNextFeedToFetch)
+func (q *Queries) GetNextFeedToFetch(ctx context.Context, nextFetchAt sql.NullTime) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getNextFeedToFetch, nextFetchAt)
 	var i Feed
 	err := row.Scan(
 		&i.ID,
@@ -317,12 +494,20 @@ func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
 		&i.Name,
 		&i.Url,
 		&i.LastFetchedAt,
+		&i.IsPodcast,
+		&i.AutoDownload,
+		&i.LastFetchStatus,
+		&i.LastFetchError,
+		&i.LastFetchNewPosts,
+		&i.AddedBy,
+		&i.NextFetchAt,
+		&i.FetchIntervalSeconds,
 	)
 	return i, err
 }
 
 const getPostsForUser = `-- name: GetPostsForUser :many
-SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.author
 FROM posts
 INNER JOIN feeds
 ON posts.feed_id = feeds.id
@@ -330,18 +515,74 @@ INNER JOIN feed_follows
 ON feeds.id = feed_follows.feed_id
 INNER JOIN users
 ON feed_follows.user_id = users.id
+LEFT JOIN folders
+ON feed_follows.folder_id = folders.id
 WHERE users.id = $1
+AND (folders.name = $2 OR $2 = '')
+AND (feeds.url = $3 OR $3 = '')
+AND (lower(posts.author) = lower($4) OR $4 = '')
+AND (EXISTS (
+    SELECT 1
+    FROM post_categories
+    WHERE post_categories.post_id = posts.id
+    AND lower(post_categories.name) = lower($5)
+) OR $5 = '')
+AND NOT EXISTS (
+    SELECT 1
+    FROM filter_rules
+    INNER JOIN post_filter_targets
+    ON post_filter_targets.field = filter_rules.field
+    WHERE filter_rules.user_id = users.id
+    AND filter_rules.action = 'exclude'
+    AND (filter_rules.feed_id IS NULL OR filter_rules.feed_id = posts.feed_id)
+    AND post_filter_targets.post_id = posts.id
+    AND CASE WHEN filter_rules.is_regex
+        THEN regexp_like(post_filter_targets.value, filter_rules.pattern, 'i')
+        ELSE strpos(lower(post_filter_targets.value), lower(filter_rules.pattern)) > 0
+    END
+)
+AND (NOT EXISTS (
+    SELECT 1
+    FROM filter_rules
+    WHERE filter_rules.user_id = users.id
+    AND filter_rules.action = 'include'
+    AND (filter_rules.feed_id IS NULL OR filter_rules.feed_id = posts.feed_id)
+) OR EXISTS (
+    SELECT 1
+    FROM filter_rules
+    INNER JOIN post_filter_targets
+    ON post_filter_targets.field = filter_rules.field
+    WHERE filter_rules.user_id = users.id
+    AND filter_rules.action = 'include'
+    AND (filter_rules.feed_id IS NULL OR filter_rules.feed_id = posts.feed_id)
+    AND post_filter_targets.post_id = posts.id
+    AND CASE WHEN filter_rules.is_regex
+        THEN regexp_like(post_filter_targets.value, filter_rules.pattern, 'i')
+        ELSE strpos(lower(post_filter_targets.value), lower(filter_rules.pattern)) > 0
+    END
+))
 ORDER BY posts.published_at DESC
-LIMIT $2
+LIMIT $6
 `
 
 type GetPostsForUserParams struct {
-	ID    uuid.UUID
-	Limit int32
+	ID         uuid.UUID
+	Folder     string
+	FeedUrl    string
+	Author     string
+	Category   string
+	LimitCount int32
 }
 
 func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]Post, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.ID, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.ID,
+		arg.Folder,
+		arg.FeedUrl,
+		arg.Author,
+		arg.Category,
+		arg.LimitCount,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -358,6 +599,7 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Author,
 		); err != nil {
 			return nil, err
 		}
@@ -372,12 +614,58 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 	return items, nil
 }
 
+const getTopCategoriesForUser = `-- name: GetTopCategoriesForUser :many
+SELECT lower(post_categories.name) AS name, COUNT(*) AS post_count
+FROM post_categories
+INNER JOIN posts
+ON post_categories.post_id = posts.id
+INNER JOIN feed_follows
+ON posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1
+GROUP BY lower(post_categories.name)
+ORDER BY post_count DESC, name ASC
+LIMIT $2
+`
+
+type GetTopCategoriesForUserParams struct {
+	UserID uuid.UUID
+	Limit  int32
+}
+
+type GetTopCategoriesForUserRow struct {
+	Name      string
+	PostCount int64
+}
+
+func (q *Queries) GetTopCategoriesForUser(ctx context.Context, arg GetTopCategoriesForUserParams) ([]GetTopCategoriesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTopCategoriesForUser, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTopCategoriesForUserRow
+	for rows.Next() {
+		var i GetTopCategoriesForUserRow
+		if err := rows.Scan(&i.Name, &i.PostCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const markFeedFetched = `-- name: MarkFeedFetched :one
 UPDATE feeds
 SET last_fetched_at = current_timestamp,
 updated_at = current_timestamp
 WHERE feeds.id = $1
-RETURNING id, created_at, updated_at, name, url, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, last_fetched_at, is_podcast, auto_download, last_fetch_status, last_fetch_error, last_fetch_new_posts, added_by, next_fetch_at, fetch_interval_seconds
 `
 
 func (q *Queries) MarkFeedFetched(ctx context.Context, id uuid.UUID) (Feed, error) {
@@ -390,6 +678,195 @@ func (q *Queries) MarkFeedFetched(ctx context.Context, id uuid.UUID) (Feed, erro
 		&i.Name,
 		&i.Url,
 		&i.LastFetchedAt,
+		&i.IsPodcast,
+		&i.AutoDownload,
+		&i.LastFetchStatus,
+		&i.LastFetchError,
+		&i.LastFetchNewPosts,
+		&i.AddedBy,
+		&i.NextFetchAt,
+		&i.FetchIntervalSeconds,
 	)
 	return i, err
 }
+
+const mergeFeedFollows = `-- name: MergeFeedFollows :exec
+UPDATE feed_follows
+SET feed_id = $1,
+updated_at = current_timestamp
+WHERE feed_id = $2
+AND user_id NOT IN (
+    SELECT user_id
+    FROM feed_follows
+    WHERE feed_id = $1
+)
+`
+
+type MergeFeedFollowsParams struct {
+	ToFeedID   uuid.UUID
+	FromFeedID uuid.UUID
+}
+
+func (q *Queries) MergeFeedFollows(ctx context.Context, arg MergeFeedFollowsParams) error {
+	_, err := q.db.ExecContext(ctx, mergeFeedFollows, arg.ToFeedID, arg.FromFeedID)
+	return err
+}
+
+const moveFeedPosts = `-- name: MoveFeedPosts :exec
+UPDATE posts
+SET feed_id = $1
+WHERE feed_id = $2
+`
+
+type MoveFeedPostsParams struct {
+	ToFeedID   uuid.UUID
+	FromFeedID uuid.UUID
+}
+
+func (q *Queries) MoveFeedPosts(ctx context.Context, arg MoveFeedPostsParams) error {
+	_, err := q.db.ExecContext(ctx, moveFeedPosts, arg.ToFeedID, arg.FromFeedID)
+	return err
+}
+
+const postponeFeedFetch = `-- name: PostponeFeedFetch :exec
+UPDATE feeds
+SET next_fetch_at = $2
+WHERE id = $1
+`
+
+type PostponeFeedFetchParams struct {
+	ID          uuid.UUID
+	NextFetchAt sql.NullTime
+}
+
+func (q *Queries) PostponeFeedFetch(ctx context.Context, arg PostponeFeedFetchParams) error {
+	_, err := q.db.ExecContext(ctx, postponeFeedFetch, arg.ID, arg.NextFetchAt)
+	return err
+}
+
+const recordFeedFetch = `-- name: RecordFeedFetch :exec
+UPDATE feeds
+SET last_fetch_status = $2,
+last_fetch_error = $3,
+last_fetch_new_posts = $4,
+next_fetch_at = $5,
+updated_at = current_timestamp
+WHERE id = $1
+`
+
+type RecordFeedFetchParams struct {
+	ID                uuid.UUID
+	LastFetchStatus   sql.NullString
+	LastFetchError    sql.NullString
+	LastFetchNewPosts sql.NullInt32
+	NextFetchAt       sql.NullTime
+}
+
+func (q *Queries) RecordFeedFetch(ctx context.Context, arg RecordFeedFetchParams) error {
+	_, err := q.db.ExecContext(ctx, recordFeedFetch,
+		arg.ID,
+		arg.LastFetchStatus,
+		arg.LastFetchError,
+		arg.LastFetchNewPosts,
+		arg.NextFetchAt,
+	)
+	return err
+}
+
+const renameFeed = `-- name: RenameFeed :execrows
+UPDATE feeds
+SET name = $2,
+updated_at = current_timestamp
+WHERE url = $1
+`
+
+type RenameFeedParams struct {
+	Url  string
+	Name string
+}
+
+func (q *Queries) RenameFeed(ctx context.Context, arg RenameFeedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, renameFeed, arg.Url, arg.Name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedAutoDownload = `-- name: SetFeedAutoDownload :execrows
+UPDATE feeds
+SET auto_download = $2,
+updated_at = current_timestamp
+WHERE feeds.url = $1
+`
+
+type SetFeedAutoDownloadParams struct {
+	Url          string
+	AutoDownload bool
+}
+
+func (q *Queries) SetFeedAutoDownload(ctx context.Context, arg SetFeedAutoDownloadParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedAutoDownload, arg.Url, arg.AutoDownload)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedFetchInterval = `-- name: SetFeedFetchInterval :execrows
+UPDATE feeds
+SET fetch_interval_seconds = $2,
+next_fetch_at = NULL,
+updated_at = current_timestamp
+WHERE url = $1
+`
+
+type SetFeedFetchIntervalParams struct {
+	Url                  string
+	FetchIntervalSeconds sql.NullInt32
+}
+
+func (q *Queries) SetFeedFetchInterval(ctx context.Context, arg SetFeedFetchIntervalParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedFetchInterval, arg.Url, arg.FetchIntervalSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedIsPodcast = `-- name: SetFeedIsPodcast :exec
+UPDATE feeds
+SET is_podcast = $2,
+updated_at = current_timestamp
+WHERE feeds.id = $1
+`
+
+type SetFeedIsPodcastParams struct {
+	ID        uuid.UUID
+	IsPodcast bool
+}
+
+func (q *Queries) SetFeedIsPodcast(ctx context.Context, arg SetFeedIsPodcastParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedIsPodcast, arg.ID, arg.IsPodcast)
+	return err
+}
+
+const setFeedUrl = `-- name: SetFeedUrl :execrows
+UPDATE feeds
+SET url = $1,
+updated_at = current_timestamp
+WHERE url = $2
+`
+
+type SetFeedUrlParams struct {
+	NewUrl string
+	OldUrl string
+}
+
+func (q *Queries) SetFeedUrl(ctx context.Context, arg SetFeedUrlParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedUrl, arg.NewUrl, arg.OldUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
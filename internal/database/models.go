@@ -11,13 +11,38 @@ import (
 	"github.com/google/uuid"
 )
 
+type Enclosure struct {
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	PostID          uuid.UUID
+	Url             string
+	MimeType        string
+	Length          sql.NullInt64
+	DurationSeconds sql.NullInt32
+	ImageUrl        sql.NullString
+	LocalPath       sql.NullString
+	DownloadedBytes sql.NullInt64
+	Sha256          sql.NullString
+	DownloadedAt    sql.NullTime
+	PlayedAt        sql.NullTime
+}
+
 type Feed struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           string
-	LastFetchedAt sql.NullTime
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Name                 string
+	Url                  string
+	LastFetchedAt        sql.NullTime
+	IsPodcast            bool
+	AutoDownload         bool
+	LastFetchStatus      sql.NullString
+	LastFetchError       sql.NullString
+	LastFetchNewPosts    sql.NullInt32
+	AddedBy              uuid.NullUUID
+	NextFetchAt          sql.NullTime
+	FetchIntervalSeconds sql.NullInt32
 }
 
 type FeedFollow struct {
@@ -26,6 +51,27 @@ type FeedFollow struct {
 	UpdatedAt time.Time
 	UserID    uuid.UUID
 	FeedID    uuid.UUID
+	FolderID  uuid.NullUUID
+}
+
+type FilterRule struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Action    string
+	Field     string
+	Pattern   string
+	IsRegex   bool
+	FeedID    uuid.NullUUID
+}
+
+type Folder struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
 }
 
 type Post struct {
@@ -37,11 +83,51 @@ type Post struct {
 	Description sql.NullString
 	PublishedAt sql.NullTime
 	FeedID      uuid.UUID
+	Author      sql.NullString
+}
+
+type PostCategory struct {
+	PostID uuid.UUID
+	Name   string
+}
+
+type PostFilterTarget struct {
+	PostID uuid.UUID
+	Field  string
+	Value  string
 }
 
 type User struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Name         string
+	Email        sql.NullString
+	LastDigestAt sql.NullTime
+	Role         string
+}
+
+type Webhook struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	Name      string
+	UserID    uuid.UUID
+	Url       string
+	Secret    sql.NullString
+	Format    string
+	Batch     bool
+	FeedID    uuid.NullUUID
+	Keyword   sql.NullString
+}
+
+type WebhookDelivery struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	WebhookID  uuid.UUID
+	PostID     uuid.NullUUID
+	PostCount  int32
+	Attempts   int32
+	StatusCode sql.NullInt32
+	Error      sql.NullString
+	Delivered  bool
 }
@@ -7,20 +7,35 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const countUsersWithRole = `-- name: CountUsersWithRole :one
+SELECT COUNT(*)
+FROM users
+WHERE role = $1
+`
+
+func (q *Queries) CountUsersWithRole(ctx context.Context, role string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUsersWithRole, role)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, name)
+INSERT INTO users (id, created_at, updated_at, name, role)
 VALUES (
     $1,
     $2,
     $3,
-    $4
+    $4,
+    $5
 )
-RETURNING id, created_at, updated_at, name
+RETURNING id, created_at, updated_at, name, email, last_digest_at, role
 `
 
 type CreateUserParams struct {
@@ -28,6 +43,7 @@ type CreateUserParams struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Name      string
+	Role      string
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -36,6 +52,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.CreatedAt,
 		arg.UpdatedAt,
 		arg.Name,
+		arg.Role,
 	)
 	var i User
 	err := row.Scan(
@@ -43,6 +60,9 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
+		&i.Email,
+		&i.LastDigestAt,
+		&i.Role,
 	)
 	return i, err
 }
@@ -56,8 +76,21 @@ func (q *Queries) DeleteUser(ctx context.Context) error {
 	return err
 }
 
+const deleteUserByName = `-- name: DeleteUserByName :execrows
+DELETE FROM users
+WHERE name = $1
+`
+
+func (q *Queries) DeleteUserByName(ctx context.Context, name string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserByName, name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getUser = `-- name: GetUser :one
-SELECT id, created_at, updated_at, name
+SELECT id, created_at, updated_at, name, email, last_digest_at, role
 FROM users
 WHERE name = $1
 LIMIT 1
@@ -71,12 +104,62 @@ func (q *Queries) GetUser(ctx context.Context, name string) (User, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
+		&i.Email,
+		&i.LastDigestAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const getUserStats = `-- name: GetUserStats :one
+SELECT
+    (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.user_id = $1) AS follows,
+    (SELECT COUNT(*) FROM folders WHERE folders.user_id = $1) AS folders,
+    (SELECT COUNT(*) FROM filter_rules WHERE filter_rules.user_id = $1) AS filter_rules,
+    (SELECT COUNT(*) FROM webhooks WHERE webhooks.user_id = $1) AS webhooks,
+    (SELECT COUNT(*)
+        FROM posts
+        INNER JOIN feed_follows
+        ON posts.feed_id = feed_follows.feed_id
+        WHERE feed_follows.user_id = $1) AS posts,
+    (SELECT COUNT(*)
+        FROM posts
+        INNER JOIN feed_follows
+        ON posts.feed_id = feed_follows.feed_id
+        WHERE feed_follows.user_id = $1
+        AND posts.created_at >= $2) AS recent_posts
+`
+
+type GetUserStatsParams struct {
+	UserID uuid.UUID
+	Since  time.Time
+}
+
+type GetUserStatsRow struct {
+	Follows     int64
+	Folders     int64
+	FilterRules int64
+	Webhooks    int64
+	Posts       int64
+	RecentPosts int64
+}
+
+func (q *Queries) GetUserStats(ctx context.Context, arg GetUserStatsParams) (GetUserStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserStats, arg.UserID, arg.Since)
+	var i GetUserStatsRow
+	err := row.Scan(
+		&i.Follows,
+		&i.Folders,
+		&i.FilterRules,
+		&i.Webhooks,
+		&i.Posts,
+		&i.RecentPosts,
 	)
 	return i, err
 }
 
 const getUsers = `-- name: GetUsers :many
-SELECT id, created_at, updated_at, name
+SELECT id, created_at, updated_at, name, email, last_digest_at, role
 FROM users
 `
 
@@ -94,6 +177,47 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Name,
+			&i.Email,
+			&i.LastDigestAt,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersWithEmail = `-- name: GetUsersWithEmail :many
+SELECT id, created_at, updated_at, name, email, last_digest_at, role
+FROM users
+WHERE email IS NOT NULL
+ORDER BY name ASC
+`
+
+func (q *Queries) GetUsersWithEmail(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersWithEmail)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Email,
+			&i.LastDigestAt,
+			&i.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -107,3 +231,77 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 	}
 	return items, nil
 }
+
+const markUserDigested = `-- name: MarkUserDigested :exec
+UPDATE users
+SET last_digest_at = $2,
+updated_at = current_timestamp
+WHERE id = $1
+`
+
+type MarkUserDigestedParams struct {
+	ID           uuid.UUID
+	LastDigestAt sql.NullTime
+}
+
+func (q *Queries) MarkUserDigested(ctx context.Context, arg MarkUserDigestedParams) error {
+	_, err := q.db.ExecContext(ctx, markUserDigested, arg.ID, arg.LastDigestAt)
+	return err
+}
+
+const renameUser = `-- name: RenameUser :execrows
+UPDATE users
+SET name = $1,
+updated_at = current_timestamp
+WHERE name = $2
+`
+
+type RenameUserParams struct {
+	NewName string
+	OldName string
+}
+
+func (q *Queries) RenameUser(ctx context.Context, arg RenameUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, renameUser, arg.NewName, arg.OldName)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setUserEmail = `-- name: SetUserEmail :exec
+UPDATE users
+SET email = $2,
+updated_at = current_timestamp
+WHERE id = $1
+`
+
+type SetUserEmailParams struct {
+	ID    uuid.UUID
+	Email sql.NullString
+}
+
+func (q *Queries) SetUserEmail(ctx context.Context, arg SetUserEmailParams) error {
+	_, err := q.db.ExecContext(ctx, setUserEmail, arg.ID, arg.Email)
+	return err
+}
+
+const setUserRole = `-- name: SetUserRole :execrows
+UPDATE users
+SET role = $2,
+updated_at = current_timestamp
+WHERE name = $1
+`
+
+type SetUserRoleParams struct {
+	Name string
+	Role string
+}
+
+func (q *Queries) SetUserRole(ctx context.Context, arg SetUserRoleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setUserRole, arg.Name, arg.Role)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,167 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Digest is the data passed to the digest templates.
+type Digest struct {
+	UserName  string
+	Since     time.Time
+	Until     time.Time
+	PostCount int
+	Feeds     []Feed
+}
+
+type Feed struct {
+	Name  string
+	URL   string
+	Posts []Post
+}
+
+type Post struct {
+	Title       string
+	URL         string
+	Description string
+	Author      string
+	PublishedAt time.Time
+}
+
+// Item is a post together with the feed it belongs to, as returned by the
+// digest queries.
+type Item struct {
+	FeedName string
+	FeedURL  string
+	Post     Post
+}
+
+// New groups items by feed, keeping the order in which feeds first appear.
+func New(userName string, since, until time.Time, items []Item) Digest {
+	digest := Digest{
+		UserName:  userName,
+		Since:     since,
+		Until:     until,
+		PostCount: len(items),
+	}
+
+	feedIndex := make(map[string]int)
+	for _, item := range items {
+		ind, ok := feedIndex[item.FeedURL]
+		if !ok {
+			ind = len(digest.Feeds)
+			feedIndex[item.FeedURL] = ind
+			digest.Feeds = append(digest.Feeds, Feed{
+				Name: item.FeedName,
+				URL:  item.FeedURL,
+			})
+		}
+
+		digest.Feeds[ind].Posts = append(digest.Feeds[ind].Posts, item.Post)
+	}
+
+	return digest
+}
+
+var templateFuncs = map[string]any{
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.DateOnly)
+	},
+	"datetime": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04 MST")
+	},
+	"summary":  summary,
+	"markdown": markdownEscape,
+}
+
+// Template names of the built-in templates. A template with the same name in
+// a Renderer's Dir replaces the built-in one.
+const (
+	EmailTextTemplate    = "email.txt.tmpl"
+	EmailHTMLTemplate    = "email.html.tmpl"
+	PageMarkdownTemplate = "page.md.tmpl"
+	PageHTMLTemplate     = "page.html.tmpl"
+)
+
+// Renderer executes digest templates. Templates ending in .html.tmpl are
+// rendered with html/template and all others with text/template.
+type Renderer struct {
+	Dir string
+}
+
+// Render executes the named template, preferring an override in r.Dir over
+// the built-in template.
+func (r Renderer) Render(name string, digest Digest) (string, error) {
+	if r.Dir != "" {
+		path := filepath.Join(r.Dir, name)
+		_, err := os.Stat(path)
+		if err == nil {
+			return RenderFile(path, digest)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	source, err := templateFS.ReadFile("templates/" + name)
+	if err != nil {
+		return "", fmt.Errorf("unknown template %s", name)
+	}
+
+	return render(name, string(source), digest)
+}
+
+// RenderFile executes the template stored at path.
+func RenderFile(path string, digest Digest) (string, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return render(filepath.Base(path), string(source), digest)
+}
+
+func render(name, source string, digest Digest) (string, error) {
+	var out bytes.Buffer
+
+	if strings.HasSuffix(name, ".html.tmpl") || strings.HasSuffix(name, ".html") {
+		tmpl, err := htmltemplate.New(name).Funcs(templateFuncs).Parse(source)
+		if err != nil {
+			return "", err
+		}
+
+		err = tmpl.Execute(&out, digest)
+		if err != nil {
+			return "", err
+		}
+
+		return out.String(), nil
+	}
+
+	tmpl, err := texttemplate.New(name).Funcs(templateFuncs).Parse(source)
+	if err != nil {
+		return "", err
+	}
+
+	err = tmpl.Execute(&out, digest)
+	if err != nil {
+		return "", err
+	}
+
+	return out.String(), nil
+}
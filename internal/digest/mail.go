@@ -0,0 +1,197 @@
+package digest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is a multipart/alternative email with a plain-text and an HTML
+// body.
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Date    time.Time
+	Text    string
+	HTML    string
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// Bytes encodes the message in RFC 5322 format, ready to be sent or saved
+// as an .eml file.
+func (m Message) Bytes() ([]byte, error) {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", m.From, err)
+	}
+
+	to, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", m.To, err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	}
+	for _, part := range parts {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := quotedprintable.NewWriter(partWriter)
+		_, err = encoder.Write([]byte(part.content))
+		if err != nil {
+			return nil, err
+		}
+
+		err = encoder.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	headers := []struct {
+		name  string
+		value string
+	}{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", m.Subject)},
+		{"Date", m.Date.Format(time.RFC1123Z)},
+		{"Message-ID", messageID(from.Address)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + writer.Boundary()},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&message, "%s: %s\r\n", header.name, header.value)
+	}
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+// Send delivers the message over SMTP. Port 465 uses implicit TLS; any
+// other port upgrades the connection with STARTTLS when the server offers
+// it.
+func Send(cfg SMTPConfig, m Message) error {
+	data, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return err
+	}
+
+	to, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return err
+	}
+
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	if cfg.Port != 465 {
+		return smtp.SendMail(address, auth, from.Address, []string{to.Address}, data)
+	}
+
+	conn, err := tls.Dial("tcp", address, &tls.Config{ServerName: cfg.Host})
+	if err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if auth != nil {
+		err = client.Auth(auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(from.Address)
+	if err != nil {
+		return err
+	}
+
+	err = client.Rcpt(to.Address)
+	if err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(data)
+	if err != nil {
+		return err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func messageID(fromAddress string) string {
+	random := make([]byte, 12)
+	rand.Read(random)
+
+	domain := "gator.local"
+	if _, host, found := strings.Cut(fromAddress, "@"); found {
+		domain = host
+	}
+
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(random), domain)
+}
@@ -0,0 +1,54 @@
+package digest
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// summaryLength is the number of characters of a post description shown in
+// a digest.
+const summaryLength = 300
+
+var (
+	htmlTags   = regexp.MustCompile(`<[^>]*>`)
+	whitespace = regexp.MustCompile(`\s+`)
+)
+
+// summary strips markup from a post description and shortens it to a
+// teaser suitable for a digest.
+func summary(description string) string {
+	text := html.UnescapeString(htmlTags.ReplaceAllString(description, " "))
+	text = strings.TrimSpace(whitespace.ReplaceAllString(text, " "))
+
+	runes := []rune(text)
+	if len(runes) <= summaryLength {
+		return text
+	}
+
+	cut := string(runes[:summaryLength])
+	if space := strings.LastIndex(cut, " "); space > summaryLength/2 {
+		cut = cut[:space]
+	}
+
+	return cut + "…"
+}
+
+var markdownSpecial = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"#", `\#`,
+	"|", `\|`,
+)
+
+// markdownEscape escapes characters that would otherwise be interpreted as
+// Markdown, so titles can be used as link text.
+func markdownEscape(text string) string {
+	return markdownSpecial.Replace(text)
+}
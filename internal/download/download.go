@@ -0,0 +1,306 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// partSuffix is appended to files that are still being downloaded. A
+// leftover part file is resumed with an HTTP Range request.
+const partSuffix = ".part"
+
+type Downloader struct {
+	Client *http.Client
+	Dir    string
+	// StallTimeout aborts a download once no data has arrived for this
+	// long. Enclosures can be large, so the download as a whole has no
+	// deadline.
+	StallTimeout time.Duration
+}
+
+// Job is a single file to download. Path is relative to the downloader's
+// directory. ExpectedSize is the size advertised by the feed, which is
+// frequently wrong and therefore only used to detect finished part files.
+type Job struct {
+	URL          string
+	Path         string
+	ExpectedSize int64
+}
+
+type Result struct {
+	Path   string
+	Size   int64
+	SHA256 string
+}
+
+// New returns a Downloader saving into dir. connectTimeout bounds
+// connecting to a server, and stallTimeout both waiting for its response
+// headers and any pause in the body.
+func New(dir string, connectTimeout, stallTimeout time.Duration) *Downloader {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   connectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   connectTimeout,
+		ResponseHeaderTimeout: stallTimeout,
+		IdleConnTimeout:       90 * time.Second,
+	}
+
+	return &Downloader{
+		Client:       &http.Client{Transport: transport},
+		Dir:          dir,
+		StallTimeout: stallTimeout,
+	}
+}
+
+// DownloadAll downloads jobs with at most concurrency downloads in flight and
+// calls done once per job. done may be called from several goroutines.
+func (d *Downloader) DownloadAll(ctx context.Context, jobs []Job, concurrency int, done func(Job, Result, error)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for _, job := range jobs {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(job Job) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			result, err := d.Download(ctx, job)
+			done(job, result, err)
+		}(job)
+	}
+
+	wg.Wait()
+}
+
+// Download fetches a single job, resuming a previous partial download when
+// possible, and verifies the size reported by the server before moving the
+// file into place.
+func (d *Downloader) Download(ctx context.Context, job Job) (Result, error) {
+	finalPath := filepath.Join(d.Dir, job.Path)
+	partPath := finalPath + partSuffix
+
+	err := os.MkdirAll(filepath.Dir(finalPath), 0755)
+	if err != nil {
+		return Result{}, err
+	}
+
+	offset := int64(0)
+	info, err := os.Stat(partPath)
+	if err == nil {
+		offset = info.Size()
+	}
+
+	if offset > 0 && job.ExpectedSize > 0 && offset == job.ExpectedSize {
+		return finish(partPath, finalPath, job.ExpectedSize)
+	}
+
+	totalSize, err := d.fetch(ctx, job.URL, partPath, offset)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return finish(partPath, finalPath, totalSize)
+}
+
+// fetch writes the body of rawURL into partPath, starting at offset, and
+// returns the total size of the file as reported by the server, or -1 if it
+// is unknown.
+func (d *Downloader) fetch(ctx context.Context, rawURL, partPath string, offset int64) (int64, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("User-Agent", "gator")
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	res, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	totalSize := int64(-1)
+
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+		start, total, err := parseContentRange(res.Header.Get("Content-Range"))
+		if err != nil {
+			return 0, err
+		}
+		if start != offset {
+			return 0, fmt.Errorf("server resumed %s at byte %d, expected %d", rawURL, start, offset)
+		}
+		flags |= os.O_APPEND
+		totalSize = total
+	case http.StatusOK:
+		// The server ignored the Range header, start over.
+		flags |= os.O_TRUNC
+		offset = 0
+		totalSize = res.ContentLength
+	case http.StatusRequestedRangeNotSatisfiable:
+		_, total, err := parseContentRange(res.Header.Get("Content-Range"))
+		if err == nil && total == offset {
+			return total, nil
+		}
+		os.Remove(partPath)
+		return 0, fmt.Errorf("could not resume %s, partial download discarded", rawURL)
+	default:
+		return 0, fmt.Errorf("downloading %s: unexpected status %s", rawURL, res.Status)
+	}
+
+	file, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return 0, err
+	}
+
+	body := io.Reader(res.Body)
+	if d.StallTimeout > 0 {
+		stalled := fmt.Errorf("downloading %s: no data received for %s", rawURL, d.StallTimeout)
+		timer := time.AfterFunc(d.StallTimeout, func() { cancel(stalled) })
+		defer timer.Stop()
+		body = &stallReader{reader: res.Body, timer: timer, timeout: d.StallTimeout}
+	}
+
+	written, err := io.Copy(file, body)
+	closeErr := file.Close()
+	if err != nil {
+		if cause := context.Cause(ctx); cause != nil && ctx.Err() != nil {
+			return 0, cause
+		}
+		return 0, err
+	}
+	if closeErr != nil {
+		return 0, closeErr
+	}
+
+	if totalSize >= 0 && offset+written != totalSize {
+		return 0, fmt.Errorf("downloading %s: got %d of %d bytes", rawURL, offset+written, totalSize)
+	}
+
+	return totalSize, nil
+}
+
+// stallReader pushes back timer every time data arrives, so that it only
+// fires once the body has been silent for timeout.
+type stallReader struct {
+	reader  io.Reader
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (r *stallReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+
+	return n, err
+}
+
+// finish verifies the size of the part file, hashes it and moves it to its
+// final location.
+func finish(partPath, finalPath string, expectedSize int64) (Result, error) {
+	size, checksum, err := Checksum(partPath)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if expectedSize >= 0 && size != expectedSize {
+		os.Remove(partPath)
+		return Result{}, fmt.Errorf("%s is %d bytes, expected %d", finalPath, size, expectedSize)
+	}
+
+	err = os.Rename(partPath, finalPath)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Path:   finalPath,
+		Size:   size,
+		SHA256: checksum,
+	}, nil
+}
+
+// Checksum returns the size and hex encoded SHA-256 of the file at path.
+func Checksum(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// parseContentRange parses a "bytes start-end/total" or "bytes */total"
+// Content-Range header. An unknown total is returned as -1.
+func parseContentRange(header string) (int64, int64, error) {
+	spec, found := strings.CutPrefix(header, "bytes ")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+
+	byteRange, totalPart, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+
+	total := int64(-1)
+	if totalPart != "*" {
+		parsed, err := strconv.ParseInt(totalPart, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid Content-Range %q", header)
+		}
+		total = parsed
+	}
+
+	if byteRange == "*" {
+		return 0, total, nil
+	}
+
+	startPart, _, found := strings.Cut(byteRange, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+
+	start, err := strconv.ParseInt(startPart, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+
+	return start, total, nil
+}
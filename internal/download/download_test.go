@@ -0,0 +1,57 @@
+package download
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownloadAbortsStalledBody(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "10")
+		w.Write([]byte("12345"))
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	downloader := New(t.TempDir(), time.Second, 200*time.Millisecond)
+
+	start := time.Now()
+	_, err := downloader.Download(context.Background(), Job{URL: server.URL, Path: "episode.mp3"})
+	if err == nil || !strings.Contains(err.Error(), "no data received") {
+		t.Fatalf("Download() error = %v, want a stall error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Download() took %s to notice the stall", elapsed)
+	}
+
+	// The part received before the stall is kept for resuming.
+	part, err := os.ReadFile(filepath.Join(downloader.Dir, "episode.mp3"+partSuffix))
+	if err != nil || string(part) != "12345" {
+		t.Fatalf("part file = %q, %v, want %q", part, err, "12345")
+	}
+}
+
+func TestDownloadTimesOutWaitingForHeaders(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	downloader := New(t.TempDir(), time.Second, 200*time.Millisecond)
+
+	_, err := downloader.Download(context.Background(), Job{URL: server.URL, Path: "episode.mp3"})
+	if err == nil {
+		t.Fatal("Download() succeeded against a server that never answers")
+	}
+}
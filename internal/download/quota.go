@@ -0,0 +1,24 @@
+package download
+
+// Evictions returns how many files must be deleted to bring their total size
+// within quotaBytes. sizes must be ordered from the most to the least
+// evictable file, and the files are deleted from the front. A quota of zero
+// or less disables eviction.
+func Evictions(sizes []int64, quotaBytes int64) int {
+	if quotaBytes <= 0 {
+		return 0
+	}
+
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+
+	evicted := 0
+	for total > quotaBytes && evicted < len(sizes) {
+		total -= sizes[evicted]
+		evicted++
+	}
+
+	return evicted
+}
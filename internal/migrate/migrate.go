@@ -0,0 +1,58 @@
+// Package migrate applies the embedded schema migrations with goose.
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ctiller15/gator/internal/storage"
+	"github.com/ctiller15/gator/sql/schema"
+	"github.com/ctiller15/gator/sql/schema/sqlite"
+	"github.com/pressly/goose/v3"
+)
+
+// NewProvider returns a goose provider for the migrations embedded in the
+// binary that match the database's dialect.
+func NewProvider(db *storage.DB) (*goose.Provider, error) {
+	if db.Dialect == storage.SQLite {
+		return goose.NewProvider(goose.DialectSQLite3, db.DB, sqlite.FS)
+	}
+
+	return goose.NewProvider(goose.DialectPostgres, db.DB, schema.FS)
+}
+
+// Version returns the version of the database schema, 0 for a database no
+// migration has been applied to.
+func Version(ctx context.Context, db *storage.DB) (int64, error) {
+	provider, err := NewProvider(db)
+	if err != nil {
+		return 0, err
+	}
+
+	current, _, err := provider.GetVersions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't read the schema version: %w", err)
+	}
+
+	return current, nil
+}
+
+// Check returns an error if the database schema is older than the newest
+// migration embedded in the binary.
+func Check(ctx context.Context, db *storage.DB) error {
+	provider, err := NewProvider(db)
+	if err != nil {
+		return err
+	}
+
+	current, target, err := provider.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("couldn't read the schema version: %w", err)
+	}
+
+	if current < target {
+		return fmt.Errorf("database schema is at version %d but this gator needs version %d, run: gator migrate up", current, target)
+	}
+
+	return nil
+}
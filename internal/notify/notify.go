@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Supported payload formats.
+const (
+	FormatJSON    = "json"
+	FormatSlack   = "slack"
+	FormatDiscord = "discord"
+)
+
+const (
+	signatureHeader = "X-Gator-Signature"
+	timestampHeader = "X-Gator-Timestamp"
+)
+
+type Notifier struct {
+	Client      *http.Client
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// Delivery describes the outcome of delivering one message, including all
+// retries.
+type Delivery struct {
+	Attempts   int
+	StatusCode int
+	Err        error
+}
+
+func (d Delivery) Delivered() bool {
+	return d.Err == nil
+}
+
+func New() *Notifier {
+	return &Notifier{
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		MaxAttempts: 3,
+		Backoff:     time.Second,
+	}
+}
+
+// Deliver posts body to url, signing it when secret is set. Network errors,
+// 429 and 5xx responses are retried with exponential backoff; any other
+// non-2xx response fails immediately.
+func (n *Notifier) Deliver(ctx context.Context, url, secret string, body []byte) Delivery {
+	var delivery Delivery
+
+	for attempt := 1; attempt <= n.MaxAttempts; attempt++ {
+		delivery.Attempts = attempt
+
+		retry := false
+		delivery.StatusCode, delivery.Err = n.post(ctx, url, secret, body)
+		switch {
+		case delivery.Err == nil:
+			return delivery
+		case delivery.StatusCode == 0,
+			delivery.StatusCode == http.StatusTooManyRequests,
+			delivery.StatusCode >= 500:
+			retry = true
+		}
+
+		if !retry || attempt == n.MaxAttempts {
+			break
+		}
+
+		wait := n.Backoff << (attempt - 1)
+		select {
+		case <-ctx.Done():
+			delivery.Err = ctx.Err()
+			return delivery
+		case <-time.After(wait):
+		}
+	}
+
+	return delivery
+}
+
+func (n *Notifier) post(ctx context.Context, url, secret string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gator")
+	req.Header.Set(timestampHeader, timestamp)
+	if secret != "" {
+		req.Header.Set(signatureHeader, Sign(secret, timestamp, body))
+	}
+
+	res, err := n.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64*1024))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook responded with %s", res.Status)
+	}
+
+	return res.StatusCode, nil
+}
+
+// Sign returns the value of the X-Gator-Signature header: the hex encoded
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with secret. Receivers should
+// recompute it using the X-Gator-Timestamp header and reject stale
+// timestamps to prevent replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
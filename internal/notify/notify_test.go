@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receiver is a webhook endpoint that answers with statuses in turn and
+// records the requests it gets.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []receivedRequest
+}
+
+type receivedRequest struct {
+	at     time.Time
+	header http.Header
+	body   []byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = append(r.requests, receivedRequest{at: time.Now(), header: req.Header, body: body})
+	status := http.StatusNoContent
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func newTestNotifier() *Notifier {
+	notifier := New()
+	notifier.Backoff = 20 * time.Millisecond
+	return notifier
+}
+
+func TestDeliverSignsBody(t *testing.T) {
+	recv := &receiver{}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	body := []byte(`{"event":"posts.created"}`)
+	delivery := newTestNotifier().Deliver(context.Background(), server.URL, "s3cret", body)
+	if !delivery.Delivered() || delivery.Attempts != 1 || delivery.StatusCode != http.StatusNoContent {
+		t.Fatalf("Deliver() = %+v, want one successful attempt", delivery)
+	}
+
+	request := recv.requests[0]
+	if got := request.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	timestamp := request.header.Get(timestampHeader)
+	if timestamp == "" {
+		t.Fatalf("missing %s header", timestampHeader)
+	}
+
+	// Recompute the signature the way a receiver would.
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(timestamp + "." + string(request.body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := request.header.Get(signatureHeader); got != want {
+		t.Errorf("%s = %q, want %q", signatureHeader, got, want)
+	}
+	if string(request.body) != string(body) {
+		t.Errorf("body = %s, want %s", request.body, body)
+	}
+}
+
+func TestDeliverWithoutSecretIsUnsigned(t *testing.T) {
+	recv := &receiver{}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	newTestNotifier().Deliver(context.Background(), server.URL, "", []byte(`{}`))
+
+	if got := recv.requests[0].header.Get(signatureHeader); got != "" {
+		t.Errorf("%s = %q, want no signature", signatureHeader, got)
+	}
+}
+
+func TestDeliverRetriesServerErrorsWithBackoff(t *testing.T) {
+	recv := &receiver{statuses: []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK}}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	notifier := newTestNotifier()
+	delivery := notifier.Deliver(context.Background(), server.URL, "", []byte(`{}`))
+	if !delivery.Delivered() || delivery.Attempts != 3 || delivery.StatusCode != http.StatusOK {
+		t.Fatalf("Deliver() = %+v, want success on the third attempt", delivery)
+	}
+
+	// The waits double: Backoff, then twice Backoff.
+	for i, want := range []time.Duration{notifier.Backoff, 2 * notifier.Backoff} {
+		if gap := recv.requests[i+1].at.Sub(recv.requests[i].at); gap < want {
+			t.Errorf("attempt %d came %s after the previous one, want at least %s", i+2, gap, want)
+		}
+	}
+}
+
+func TestDeliverGivesUpAfterMaxAttempts(t *testing.T) {
+	recv := &receiver{statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusOK}}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	delivery := newTestNotifier().Deliver(context.Background(), server.URL, "", []byte(`{}`))
+	if delivery.Delivered() || delivery.Attempts != 3 || delivery.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Deliver() = %+v, want three failed attempts", delivery)
+	}
+	if len(recv.requests) != 3 {
+		t.Errorf("receiver got %d requests, want 3", len(recv.requests))
+	}
+}
+
+func TestDeliverDoesNotRetryClientErrors(t *testing.T) {
+	recv := &receiver{statuses: []int{http.StatusNotFound}}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	delivery := newTestNotifier().Deliver(context.Background(), server.URL, "", []byte(`{}`))
+	if delivery.Delivered() || delivery.Attempts != 1 || delivery.StatusCode != http.StatusNotFound {
+		t.Fatalf("Deliver() = %+v, want one failed attempt", delivery)
+	}
+}
+
+func TestDeliverStopsWhenCancelled(t *testing.T) {
+	recv := &receiver{statuses: []int{http.StatusInternalServerError}}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	notifier := New()
+	notifier.Backoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	delivery := notifier.Deliver(ctx, server.URL, "", []byte(`{}`))
+	if delivery.Err != context.DeadlineExceeded || delivery.Attempts != 1 {
+		t.Fatalf("Deliver() = %+v, want to stop waiting for the retry", delivery)
+	}
+}
@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// discordMaxEmbeds is the number of embeds Discord accepts per message.
+const discordMaxEmbeds = 10
+
+// summaryLength bounds post descriptions in chat payloads.
+const summaryLength = 280
+
+type Post struct {
+	ID          string     `json:"id,omitempty"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Description string     `json:"description,omitempty"`
+	Author      string     `json:"author,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	FeedName    string     `json:"feed_name"`
+	FeedURL     string     `json:"feed_url"`
+}
+
+type jsonPayload struct {
+	Event  string    `json:"event"`
+	SentAt time.Time `json:"sent_at"`
+	Posts  []Post    `json:"posts"`
+}
+
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+type discordEmbed struct {
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+	Timestamp   string `json:"timestamp,omitempty"`
+	Footer      struct {
+		Text string `json:"text"`
+	} `json:"footer"`
+}
+
+type discordPayload struct {
+	Content string         `json:"content"`
+	Embeds  []discordEmbed `json:"embeds"`
+}
+
+// Message is a single request body and the posts it announces.
+type Message struct {
+	Posts []Post
+	Body  []byte
+}
+
+// Messages renders posts as the requests to send to a webhook. When batch is
+// false every post gets its own message; otherwise posts are combined into
+// as few messages as the format allows.
+func Messages(format string, batch bool, posts []Post) ([]Message, error) {
+	var groups [][]Post
+	switch {
+	case !batch:
+		for _, post := range posts {
+			groups = append(groups, []Post{post})
+		}
+	case format == FormatDiscord:
+		for start := 0; start < len(posts); start += discordMaxEmbeds {
+			end := min(start+discordMaxEmbeds, len(posts))
+			groups = append(groups, posts[start:end])
+		}
+	default:
+		groups = append(groups, posts)
+	}
+
+	var messages []Message
+	for _, group := range groups {
+		body, err := render(format, group)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, Message{
+			Posts: group,
+			Body:  body,
+		})
+	}
+
+	return messages, nil
+}
+
+func render(format string, posts []Post) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.Marshal(jsonPayload{
+			Event:  "posts.created",
+			SentAt: time.Now().UTC(),
+			Posts:  posts,
+		})
+	case FormatSlack:
+		var lines []string
+		for _, post := range posts {
+			lines = append(lines, fmt.Sprintf("*<%s|%s>* (%s)", post.URL, slackEscape(post.Title), slackEscape(post.FeedName)))
+		}
+		return json.Marshal(slackPayload{
+			Text: strings.Join(lines, "\n"),
+		})
+	case FormatDiscord:
+		payload := discordPayload{
+			Content: fmt.Sprintf("%d new post(s)", len(posts)),
+		}
+		for _, post := range posts {
+			embed := discordEmbed{
+				Title:       truncate(post.Title, 256),
+				URL:         post.URL,
+				Description: truncate(post.Description, summaryLength),
+			}
+			embed.Footer.Text = truncate(post.FeedName, 2048)
+			if post.PublishedAt != nil {
+				embed.Timestamp = post.PublishedAt.UTC().Format(time.RFC3339)
+			}
+			payload.Embeds = append(payload.Embeds, embed)
+		}
+		return json.Marshal(payload)
+	default:
+		return nil, fmt.Errorf("unknown webhook format %s", format)
+	}
+}
+
+func slackEscape(value string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(value)
+}
+
+func truncate(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+
+	return string(runes[:limit-1]) + "…"
+}
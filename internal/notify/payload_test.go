@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func samplePosts(n int) []Post {
+	published := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+
+	var posts []Post
+	for i := range n {
+		posts = append(posts, Post{
+			ID:          fmt.Sprintf("post-%d", i),
+			Title:       fmt.Sprintf("Post <%d> & more", i),
+			URL:         fmt.Sprintf("https://example.com/%d", i),
+			Description: strings.Repeat("x", 300),
+			PublishedAt: &published,
+			FeedName:    "Example",
+			FeedURL:     "https://example.com/feed.xml",
+		})
+	}
+
+	return posts
+}
+
+func TestMessagesJSON(t *testing.T) {
+	messages, err := Messages(FormatJSON, true, samplePosts(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(messages))
+	}
+
+	var payload struct {
+		Event string `json:"event"`
+		Posts []Post `json:"posts"`
+	}
+	err = json.Unmarshal(messages[0].Body, &payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload.Event != "posts.created" || len(payload.Posts) != 3 || payload.Posts[2].URL != "https://example.com/2" {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestMessagesUnbatched(t *testing.T) {
+	messages, err := Messages(FormatJSON, false, samplePosts(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 3 {
+		t.Fatalf("got %d messages, want one per post", len(messages))
+	}
+	for i, message := range messages {
+		if len(message.Posts) != 1 || message.Posts[0].ID != fmt.Sprintf("post-%d", i) {
+			t.Errorf("message %d announces %+v", i, message.Posts)
+		}
+	}
+}
+
+func TestMessagesSlack(t *testing.T) {
+	messages, err := Messages(FormatSlack, true, samplePosts(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var payload map[string]any
+	err = json.Unmarshal(messages[0].Body, &payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payload) != 1 {
+		t.Errorf("slack payload has keys %v, want only text", payload)
+	}
+
+	want := "*<https://example.com/0|Post &lt;0&gt; &amp; more>* (Example)\n*<https://example.com/1|Post &lt;1&gt; &amp; more>* (Example)"
+	if payload["text"] != want {
+		t.Errorf("text = %q, want %q", payload["text"], want)
+	}
+}
+
+func TestMessagesDiscord(t *testing.T) {
+	messages, err := Messages(FormatDiscord, true, samplePosts(12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 2 || len(messages[0].Posts) != discordMaxEmbeds || len(messages[1].Posts) != 2 {
+		t.Fatalf("got %d messages, want 12 posts split into 10 and 2", len(messages))
+	}
+
+	var payload struct {
+		Content string `json:"content"`
+		Embeds  []struct {
+			Title       string `json:"title"`
+			URL         string `json:"url"`
+			Description string `json:"description"`
+			Timestamp   string `json:"timestamp"`
+			Footer      struct {
+				Text string `json:"text"`
+			} `json:"footer"`
+		} `json:"embeds"`
+	}
+	err = json.Unmarshal(messages[1].Body, &payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.Content != "2 new post(s)" || len(payload.Embeds) != 2 {
+		t.Fatalf("payload = %+v", payload)
+	}
+	embed := payload.Embeds[0]
+	if embed.Title != "Post <10> & more" || embed.URL != "https://example.com/10" || embed.Footer.Text != "Example" {
+		t.Errorf("embed = %+v", embed)
+	}
+	if embed.Timestamp != "2024-05-01T08:30:00Z" {
+		t.Errorf("timestamp = %q", embed.Timestamp)
+	}
+	if n := len([]rune(embed.Description)); n != summaryLength || !strings.HasSuffix(embed.Description, "…") {
+		t.Errorf("description is %d runes, want it truncated to %d", n, summaryLength)
+	}
+}
+
+func TestMessagesUnknownFormat(t *testing.T) {
+	_, err := Messages("xml", true, samplePosts(1))
+	if err == nil {
+		t.Fatal("Messages() accepted an unknown format")
+	}
+}
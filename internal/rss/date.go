@@ -0,0 +1,144 @@
+package rss
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// dateLayouts are tried in order after ParseDate has normalised the input:
+// weekday names are removed and named zones are rewritten as numeric offsets.
+var dateLayouts = []string{
+	// RFC 822 / 1123 family used by RSS, with and without seconds.
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04 -0700",
+	"2 January 2006 15:04:05 -0700",
+	"2 January 2006 15:04 -0700",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04",
+	"2 Jan 2006",
+
+	// ISO 8601 / RFC 3339 family used by Atom and dc:date.
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02T15:04:05.999999999 -0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+
+	// Less common layouts seen in the wild.
+	"Jan 2, 2006 15:04:05 -0700",
+	"Jan 2, 2006 15:04 -0700",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"Jan 2 15:04:05 -0700 2006",
+	"Jan 2 15:04:05 2006",
+}
+
+// zoneOffsets maps the named time zones that show up in feeds to their UTC
+// offset. time.Parse accepts unknown abbreviations but silently treats them
+// as UTC, so they are rewritten before parsing.
+var zoneOffsets = map[string]string{
+	"UT":   "+0000",
+	"UTC":  "+0000",
+	"GMT":  "+0000",
+	"Z":    "+0000",
+	"EST":  "-0500",
+	"EDT":  "-0400",
+	"CST":  "-0600",
+	"CDT":  "-0500",
+	"MST":  "-0700",
+	"MDT":  "-0600",
+	"PST":  "-0800",
+	"PDT":  "-0700",
+	"AKST": "-0900",
+	"AKDT": "-0800",
+	"HST":  "-1000",
+	"BST":  "+0100",
+	"IST":  "+0530",
+	"WET":  "+0000",
+	"WEST": "+0100",
+	"CET":  "+0100",
+	"CEST": "+0200",
+	"EET":  "+0200",
+	"EEST": "+0300",
+	"MSK":  "+0300",
+	"JST":  "+0900",
+	"KST":  "+0900",
+	"AEST": "+1000",
+	"AEDT": "+1100",
+	"ACST": "+0930",
+	"ACDT": "+1030",
+	"AWST": "+0800",
+	"NZST": "+1200",
+	"NZDT": "+1300",
+}
+
+var (
+	weekdayPrefix = regexp.MustCompile(`^(?i)(mon|tue|wed|thu|fri|sat|sun)[a-z]*\.?,?\s+`)
+	trailingZone  = regexp.MustCompile(`\s*\(?([A-Za-z]{1,4})\)?$`)
+	gmtOffset     = regexp.MustCompile(`(?i)\s*(?:GMT|UTC)\s*([+-]\d{1,2}):?(\d{2})?$`)
+	shortOffset   = regexp.MustCompile(`\s([+-])(\d):?(\d{2})$`)
+	colonOffset   = regexp.MustCompile(`\s([+-]\d{2}):(\d{2})$`)
+	whitespace    = regexp.MustCompile(`\s+`)
+)
+
+// ParseDate parses a publication date as found in RSS pubDate, Atom
+// published/updated and Dublin Core dc:date elements. It tolerates missing
+// weekdays, single-digit days and hours, missing seconds, named time zones
+// and ISO dates without a zone (which are assumed to be UTC).
+func ParseDate(value string) (time.Time, error) {
+	normalised := normaliseDate(value)
+	if normalised == "" {
+		return time.Time{}, fmt.Errorf("empty date")
+	}
+
+	for _, layout := range dateLayouts {
+		parsed, err := time.Parse(layout, normalised)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognised date format %q", value)
+}
+
+func normaliseDate(value string) string {
+	value = whitespace.ReplaceAllString(strings.TrimSpace(value), " ")
+	value = weekdayPrefix.ReplaceAllString(value, "")
+
+	// "GMT+2", "UTC-05:00"
+	if match := gmtOffset.FindStringSubmatch(value); match != nil {
+		sign, hours := match[1][:1], match[1][1:]
+		if len(hours) == 1 {
+			hours = "0" + hours
+		}
+		minutes := match[2]
+		if minutes == "" {
+			minutes = "00"
+		}
+		value = value[:len(value)-len(match[0])] + " " + sign + hours + minutes
+	}
+
+	// "EST", "(PDT)", "Z" after a space
+	if match := trailingZone.FindStringSubmatch(value); match != nil && strings.Contains(value, " ") {
+		if offset, ok := zoneOffsets[strings.ToUpper(match[1])]; ok {
+			value = value[:len(value)-len(match[0])] + " " + offset
+		}
+	}
+
+	// "+5:30" -> "+0530"
+	value = shortOffset.ReplaceAllString(value, " ${1}0${2}${3}")
+	// "+05:00" after a space -> "+0500"
+	value = colonOffset.ReplaceAllString(value, " ${1}${2}")
+
+	return value
+}
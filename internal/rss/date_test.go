@@ -0,0 +1,85 @@
+package rss
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Time
+	}{
+		{"RFC 1123", "Mon, 02 Jan 2006 15:04:05 GMT", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"RFC 1123 numeric zone", "Tue, 10 Jun 2003 04:00:00 +0000", time.Date(2003, 6, 10, 4, 0, 0, 0, time.UTC)},
+		{"single-digit day", "Wed, 4 Sep 2024 09:30:00 +0000", time.Date(2024, 9, 4, 9, 30, 0, 0, time.UTC)},
+		{"single-digit day and hour", "Fri, 1 Mar 2024 7:05:00 +0000", time.Date(2024, 3, 1, 7, 5, 0, 0, time.UTC)},
+		{"EST", "Thu, 15 Feb 2024 10:00:00 EST", time.Date(2024, 2, 15, 15, 0, 0, 0, time.UTC)},
+		{"PDT", "Sat, 20 Jul 2024 18:45:00 PDT", time.Date(2024, 7, 21, 1, 45, 0, 0, time.UTC)},
+		{"zone in parentheses", "Mon, 05 Aug 2024 12:00:00 (CEST)", time.Date(2024, 8, 5, 10, 0, 0, 0, time.UTC)},
+		{"missing seconds", "Sun, 07 Jan 2024 08:15 +0100", time.Date(2024, 1, 7, 7, 15, 0, 0, time.UTC)},
+		{"missing weekday", "12 Dec 2023 23:59:59 -0800", time.Date(2023, 12, 13, 7, 59, 59, 0, time.UTC)},
+		{"full month name", "3 October 2022 14:00:00 +0000", time.Date(2022, 10, 3, 14, 0, 0, 0, time.UTC)},
+		{"two-digit year", "Mon, 02 Jan 06 15:04:05 +0000", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"GMT offset", "Tue, 14 May 2024 10:00:00 GMT+2", time.Date(2024, 5, 14, 8, 0, 0, 0, time.UTC)},
+		{"offset with colon", "14 May 2024 10:00:00 +05:30", time.Date(2024, 5, 14, 4, 30, 0, 0, time.UTC)},
+		{"extra whitespace", "  Mon,  02 Jan 2006\t15:04:05 GMT ", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"RFC 3339", "2024-03-10T12:30:00Z", time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)},
+		{"RFC 3339 offset", "2024-03-10T12:30:00-05:00", time.Date(2024, 3, 10, 17, 30, 0, 0, time.UTC)},
+		{"RFC 3339 fraction", "2024-03-10T12:30:00.123Z", time.Date(2024, 3, 10, 12, 30, 0, 123000000, time.UTC)},
+		{"ISO without zone", "2024-03-10T12:30:00", time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)},
+		{"ISO without seconds or zone", "2024-03-10T12:30", time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)},
+		{"ISO with space", "2024-03-10 12:30:00", time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)},
+		{"date only", "2024-03-10", time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"US style", "Mar 10, 2024", time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"ctime style", "Sun Mar 10 12:30:00 +0000 2024", time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDate(tt.value)
+			if err != nil {
+				t.Fatalf("ParseDate(%q) error = %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDate(%q) = %s, want %s", tt.value, got.UTC(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDateRejects(t *testing.T) {
+	for _, value := range []string{"", "   ", "yesterday", "not a date", "32 Jan 2024 10:00:00 +0000", "2024-13-01"} {
+		if got, err := ParseDate(value); err == nil {
+			t.Errorf("ParseDate(%q) = %s, want an error", value, got)
+		}
+	}
+}
+
+func TestPublishedDate(t *testing.T) {
+	feed, err := parseFeed([]byte(`<?xml version="1.0"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel>
+<title>t</title>
+<item><title>both</title><pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate><dc:date>2020-01-01T00:00:00Z</dc:date></item>
+<item><title>dc only</title><dc:date>2024-03-10T12:30:00+01:00</dc:date></item>
+<item><title>undated</title></item>
+</channel>
+</rss>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"Mon, 02 Jan 2006 15:04:05 GMT", "2024-03-10T12:30:00+01:00", ""}
+	for i, item := range feed.Channel.Item {
+		if got := item.PublishedDate(); got != want[i] {
+			t.Errorf("%s: PublishedDate() = %q, want %q", item.Title, got, want[i])
+		}
+	}
+
+	parsed, err := ParseDate(feed.Channel.Item[1].PublishedDate())
+	if err != nil || !parsed.Equal(time.Date(2024, 3, 10, 11, 30, 0, 0, time.UTC)) {
+		t.Errorf("ParseDate(dc:date) = %s, %v", parsed, err)
+	}
+}
@@ -0,0 +1,190 @@
+package rss
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RSSEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type ITunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+type MediaContent struct {
+	URL      string `xml:"url,attr"`
+	Type     string `xml:"type,attr"`
+	Medium   string `xml:"medium,attr"`
+	FileSize string `xml:"fileSize,attr"`
+	Duration string `xml:"duration,attr"`
+}
+
+type MediaThumbnail struct {
+	URL string `xml:"url,attr"`
+}
+
+// Enclosure is a media attachment of an item, merged from <enclosure>,
+// media:content and the iTunes episode metadata.
+type Enclosure struct {
+	URL      string
+	MIMEType string
+	Length   int64
+	Duration time.Duration
+	ImageURL string
+}
+
+// IsAudioVisual reports whether the enclosure is an audio or video file.
+func (e Enclosure) IsAudioVisual() bool {
+	return strings.HasPrefix(e.MIMEType, "audio/") || strings.HasPrefix(e.MIMEType, "video/")
+}
+
+// Media returns the enclosures of the item. Enclosures listed both as
+// <enclosure> and media:content are only returned once.
+func (item RSSItem) Media() []Enclosure {
+	duration, _ := ParseITunesDuration(item.ITunesDuration)
+
+	imageURL := item.ITunesImage.Href
+	if imageURL == "" && len(item.MediaThumbnail) > 0 {
+		imageURL = item.MediaThumbnail[0].URL
+	}
+
+	var media []Enclosure
+	seen := make(map[string]bool)
+
+	for _, enclosure := range item.Enclosures {
+		if enclosure.URL == "" || seen[enclosure.URL] {
+			continue
+		}
+		seen[enclosure.URL] = true
+
+		length, _ := strconv.ParseInt(strings.TrimSpace(enclosure.Length), 10, 64)
+		media = append(media, Enclosure{
+			URL:      enclosure.URL,
+			MIMEType: mimeType(enclosure.Type, "", enclosure.URL),
+			Length:   length,
+			Duration: duration,
+			ImageURL: imageURL,
+		})
+	}
+
+	for _, content := range item.MediaContent {
+		if content.URL == "" || seen[content.URL] {
+			continue
+		}
+		seen[content.URL] = true
+
+		length, _ := strconv.ParseInt(strings.TrimSpace(content.FileSize), 10, 64)
+		contentDuration := duration
+		if seconds, err := strconv.ParseFloat(strings.TrimSpace(content.Duration), 64); err == nil {
+			contentDuration = time.Duration(seconds * float64(time.Second))
+		}
+
+		media = append(media, Enclosure{
+			URL:      content.URL,
+			MIMEType: mimeType(content.Type, content.Medium, content.URL),
+			Length:   length,
+			Duration: contentDuration,
+			ImageURL: imageURL,
+		})
+	}
+
+	return media
+}
+
+// IsPodcast reports whether the feed looks like a podcast: it either carries
+// iTunes channel metadata or at least one item has an audio or video
+// enclosure.
+func (feed *RSSFeed) IsPodcast() bool {
+	channel := feed.Channel
+	if channel.ITunesAuthor != "" || channel.ITunesType != "" || channel.ITunesImage.Href != "" {
+		return true
+	}
+
+	for _, item := range channel.Item {
+		for _, enclosure := range item.Media() {
+			if enclosure.IsAudioVisual() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ParseITunesDuration parses an itunes:duration value, which may be given
+// as plain seconds, MM:SS or HH:MM:SS.
+func ParseITunesDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var seconds float64
+	for _, part := range parts {
+		number, err := strconv.ParseFloat(part, 64)
+		if err != nil || number < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		seconds = seconds*60 + number
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// mediaExtensions covers the podcast formats missing from Go's builtin MIME
+// table, which otherwise depends on the system's mime.types.
+var mediaExtensions = map[string]string{
+	".mp3":  "audio/mpeg",
+	".m4a":  "audio/mp4",
+	".aac":  "audio/aac",
+	".ogg":  "audio/ogg",
+	".oga":  "audio/ogg",
+	".opus": "audio/opus",
+	".flac": "audio/flac",
+	".wav":  "audio/wav",
+	".mp4":  "video/mp4",
+	".m4v":  "video/x-m4v",
+	".mov":  "video/quicktime",
+	".webm": "video/webm",
+}
+
+func mimeType(declared, medium, rawURL string) string {
+	declared = strings.TrimSpace(declared)
+	if declared != "" {
+		return strings.ToLower(declared)
+	}
+
+	if parsed, err := url.Parse(rawURL); err == nil {
+		extension := strings.ToLower(path.Ext(parsed.Path))
+		if known, ok := mediaExtensions[extension]; ok {
+			return known
+		}
+		if guessed := mime.TypeByExtension(extension); guessed != "" {
+			mediaType, _, err := mime.ParseMediaType(guessed)
+			if err == nil {
+				return mediaType
+			}
+		}
+	}
+
+	switch medium {
+	case "audio", "video", "image":
+		return medium + "/*"
+	}
+
+	return "application/octet-stream"
+}
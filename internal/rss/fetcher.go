@@ -0,0 +1,224 @@
+package rss
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"time"
+)
+
+// Version is the gator version sent in the User-Agent. Release builds set it
+// with -ldflags "-X github.com/ctiller15/gator/internal/rss.Version=1.2.3";
+// otherwise the module version from the build info is used.
+var Version = ""
+
+// ContactURL is where site owners can find out about gator, sent in the
+// User-Agent unless Options.ContactURL replaces it.
+const ContactURL = "https://github.com/ctiller15/gator"
+
+// Defaults used for zero Options fields.
+const (
+	defaultConnectTimeout = 10 * time.Second
+	defaultTimeout        = time.Minute
+	defaultMaxBodySize    = 10 * 1024 * 1024
+	defaultMaxRedirects   = 5
+)
+
+// Options configure a Fetcher. Zero values select the defaults.
+type Options struct {
+	// ConnectTimeout bounds establishing a connection, including the TLS
+	// handshake. Defaults to 10 seconds.
+	ConnectTimeout time.Duration
+	// Timeout bounds a whole request, from connecting until the body is
+	// read. Defaults to a minute.
+	Timeout time.Duration
+	// MaxBodySize is the largest feed read, in bytes. Defaults to 10 MiB.
+	MaxBodySize int64
+	// MaxRedirects is the number of redirects followed. Defaults to 5.
+	MaxRedirects int
+	// Proxy is the URL of the proxy to use. Empty uses the HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables.
+	Proxy string
+	// ContactURL is sent in the User-Agent. Defaults to ContactURL.
+	ContactURL string
+	// Limiter spaces out requests per host. Nil means no limits.
+	Limiter *Limiter
+	// CheckRobots makes the Fetcher read robots.txt before fetching.
+	CheckRobots bool
+}
+
+// Fetcher downloads feeds over a shared HTTP client, so that connections to
+// a host are reused between fetches.
+type Fetcher struct {
+	Client      *http.Client
+	UserAgent   string
+	MaxBodySize int64
+	Limiter     *Limiter
+	// Robots is nil unless robots.txt is checked.
+	Robots *Robots
+}
+
+// NewFetcher returns a Fetcher configured by options.
+func NewFetcher(options Options) (*Fetcher, error) {
+	connectTimeout := cmpOr(options.ConnectTimeout, defaultConnectTimeout)
+	maxRedirects := cmpOr(options.MaxRedirects, defaultMaxRedirects)
+
+	proxy := http.ProxyFromEnvironment
+	if options.Proxy != "" {
+		proxyURL, err := url.Parse(options.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url %q", options.Proxy)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	transport := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   connectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout: connectTimeout,
+		MaxIdleConnsPerHost: 2,
+		IdleConnTimeout:     90 * time.Second,
+		ForceAttemptHTTP2:   true,
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   cmpOr(options.Timeout, defaultTimeout),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}
+
+	fetcher := &Fetcher{
+		Client:      client,
+		UserAgent:   userAgent(cmp.Or(options.ContactURL, ContactURL)),
+		MaxBodySize: cmpOr(options.MaxBodySize, defaultMaxBodySize),
+		Limiter:     options.Limiter,
+	}
+	if options.CheckRobots {
+		fetcher.Robots = NewRobots(client, fetcher.UserAgent)
+	}
+
+	return fetcher, nil
+}
+
+// Delay returns how long a fetch of feedURL would wait for its host's rate
+// limit if it were made now, which is zero without a Limiter.
+func (f *Fetcher) Delay(feedURL string) time.Duration {
+	if f.Limiter == nil {
+		return 0
+	}
+
+	return f.Limiter.Delay(feedURL)
+}
+
+// SetCrawlDelay spaces out fetches from the host of feedURL by at least
+// delay. It does nothing without a Limiter.
+func (f *Fetcher) SetCrawlDelay(feedURL string, delay time.Duration) {
+	if f.Limiter == nil {
+		return
+	}
+
+	f.Limiter.SetCrawlDelay(feedURL, delay)
+}
+
+// FetchFeed downloads and parses the feed at feedURL, waiting until the
+// Limiter allows a request to its host. Responses other than 2xx are
+// returned as a *StatusError without being parsed.
+func (f *Fetcher) FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
+	if f.Limiter != nil {
+		release, err := f.Limiter.Acquire(ctx, feedURL)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", f.UserAgent)
+	req.Header.Set("Accept", "application/rss+xml, application/xml;q=0.9, text/xml;q=0.8, */*;q=0.1")
+
+	res, err := f.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		// Reading a little of the body lets the connection be reused.
+		io.Copy(io.Discard, io.LimitReader(res.Body, 64*1024))
+
+		statusErr := &StatusError{StatusCode: res.StatusCode}
+		if res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable {
+			statusErr.RetryAfter = retryAfter(res.Header.Get("Retry-After"), time.Now())
+		}
+		return nil, statusErr
+	}
+
+	body, err := readLimited(res.Body, f.MaxBodySize)
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := parseFeed(body)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.MaxAge = maxAge(res.Header.Get("Cache-Control"))
+	return feed, nil
+}
+
+// readLimited reads r, failing once it is longer than limit bytes rather than
+// buffering an endless or oversized response.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("feed is larger than %d bytes", limit)
+	}
+
+	return body, nil
+}
+
+// userAgent returns the User-Agent gator sends, such as
+// "gator/1.2.3 (+https://github.com/ctiller15/gator)". The product token
+// stays UserAgent so that robots.txt rules for gator apply.
+func userAgent(contactURL string) string {
+	version := Version
+	if version == "" {
+		version = "dev"
+		if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			version = info.Main.Version
+		}
+	}
+
+	return fmt.Sprintf("%s/%s (+%s)", UserAgent, version, contactURL)
+}
+
+// cmpOr returns value, or fallback when value is zero or negative.
+func cmpOr[T int | int64 | time.Duration](value, fallback T) T {
+	if value > 0 {
+		return value
+	}
+
+	return fallback
+}
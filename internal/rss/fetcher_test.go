@@ -0,0 +1,210 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleFeed = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Example</title>
+<item><title>Hello</title><link>https://example.com/hello</link></item>
+</channel></rss>`
+
+func newTestFetcher(t *testing.T, options Options) *Fetcher {
+	t.Helper()
+
+	fetcher, err := NewFetcher(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fetcher
+}
+
+func TestFetchFeed(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.Header().Set("Cache-Control", "public, max-age=1800")
+		w.Write([]byte(sampleFeed))
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, Options{ContactURL: "https://example.com/about-my-gator"})
+	feed, err := fetcher.FetchFeed(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if feed.Channel.Title != "Example" || len(feed.Channel.Item) != 1 {
+		t.Errorf("feed = %+v", feed.Channel)
+	}
+	if feed.MaxAge != 30*time.Minute {
+		t.Errorf("MaxAge = %s, want 30m", feed.MaxAge)
+	}
+
+	// gator/<version> (+contact), keeping the robots.txt product token.
+	userAgent := header.Get("User-Agent")
+	product, contact, ok := strings.Cut(userAgent, " ")
+	version, found := strings.CutPrefix(product, UserAgent+"/")
+	if !ok || !found || version == "" || contact != "(+https://example.com/about-my-gator)" {
+		t.Errorf("User-Agent = %q, want gator/<version> (+https://example.com/about-my-gator)", userAgent)
+	}
+	if !strings.Contains(header.Get("Accept"), "application/rss+xml") {
+		t.Errorf("Accept = %q", header.Get("Accept"))
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	defer func(version string) { Version = version }(Version)
+	Version = "1.2.3"
+
+	if got, want := userAgent(ContactURL), "gator/1.2.3 (+https://github.com/ctiller15/gator)"; got != want {
+		t.Errorf("userAgent() = %q, want %q", got, want)
+	}
+	if got := newTestFetcher(t, Options{}).UserAgent; got != "gator/1.2.3 (+"+ContactURL+")" {
+		t.Errorf("default UserAgent = %q", got)
+	}
+}
+
+func TestFetchFeedStatusError(t *testing.T) {
+	retryAt := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		want       time.Duration
+	}{
+		{"not found", http.StatusNotFound, "", 0},
+		{"retry-after ignored on other statuses", http.StatusInternalServerError, "120", 0},
+		{"too many requests", http.StatusTooManyRequests, "120", 2 * time.Minute},
+		{"unavailable until a date", http.StatusServiceUnavailable, retryAt, time.Hour},
+		{"unavailable without retry-after", http.StatusServiceUnavailable, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(sampleFeed))
+			}))
+			defer server.Close()
+
+			_, err := newTestFetcher(t, Options{}).FetchFeed(context.Background(), server.URL)
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("FetchFeed() error = %v, want a *StatusError", err)
+			}
+			if statusErr.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", statusErr.StatusCode, tt.status)
+			}
+			// An HTTP date has a resolution of a second.
+			if statusErr.RetryAfter > tt.want || statusErr.RetryAfter < tt.want-time.Second {
+				t.Errorf("RetryAfter = %s, want %s", statusErr.RetryAfter, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchFeedMaxBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleFeed))
+	}))
+	defer server.Close()
+
+	size := int64(len(sampleFeed))
+
+	_, err := newTestFetcher(t, Options{MaxBodySize: size}).FetchFeed(context.Background(), server.URL)
+	if err != nil {
+		t.Errorf("feed of exactly MaxBodySize: %v", err)
+	}
+
+	_, err = newTestFetcher(t, Options{MaxBodySize: size - 1}).FetchFeed(context.Background(), server.URL)
+	if err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("oversized feed: %v, want it rejected", err)
+	}
+}
+
+func TestReadLimited(t *testing.T) {
+	tests := []struct {
+		body    string
+		limit   int64
+		wantErr bool
+	}{
+		{"", 10, false},
+		{"0123456789", 10, false},
+		{"0123456789a", 10, true},
+		{strings.Repeat("x", 1<<20), 1024, true},
+	}
+
+	for _, tt := range tests {
+		body, err := readLimited(strings.NewReader(tt.body), tt.limit)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("readLimited(%d bytes, %d) error = %v, want error %v", len(tt.body), tt.limit, err, tt.wantErr)
+		}
+		if err == nil && string(body) != tt.body {
+			t.Errorf("readLimited() = %q, want %q", body, tt.body)
+		}
+	}
+}
+
+func TestFetchFeedMaxRedirects(t *testing.T) {
+	// /hops/n redirects n times before serving the feed.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hops, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hops/"))
+		if hops > 0 {
+			http.Redirect(w, r, fmt.Sprintf("/hops/%d", hops-1), http.StatusFound)
+			return
+		}
+		w.Write([]byte(sampleFeed))
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, Options{MaxRedirects: 2})
+
+	_, err := fetcher.FetchFeed(context.Background(), server.URL+"/hops/2")
+	if err != nil {
+		t.Errorf("2 redirects with MaxRedirects 2: %v", err)
+	}
+
+	_, err = fetcher.FetchFeed(context.Background(), server.URL+"/hops/3")
+	if err == nil || !strings.Contains(err.Error(), "stopped after 2 redirects") {
+		t.Errorf("3 redirects with MaxRedirects 2: %v, want it stopped", err)
+	}
+
+	_, err = newTestFetcher(t, Options{}).FetchFeed(context.Background(), server.URL+"/hops/5")
+	if err != nil {
+		t.Errorf("5 redirects with the default limit: %v", err)
+	}
+}
+
+func TestFetchFeedWaitsForLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleFeed))
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(t, Options{Limiter: NewLimiter(Limit{RequestsPerMinute: 600}, nil)})
+
+	start := time.Now()
+	for range 2 {
+		_, err := fetcher.FetchFeed(context.Background(), server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("two fetches took %s, want them 100ms apart", elapsed)
+	}
+}
@@ -0,0 +1,177 @@
+package rss
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit bounds the requests made to one host.
+type Limit struct {
+	// RequestsPerMinute is the rate at which requests may start. Requests
+	// are spaced evenly rather than sent in bursts. Zero means unlimited.
+	RequestsPerMinute int
+	// MaxConnections is the number of requests that may be in flight at
+	// once. Zero means unlimited.
+	MaxConnections int
+}
+
+// Limiter keeps the requests made to each host within its Limit, so that
+// many feeds on one domain do not hammer it.
+type Limiter struct {
+	defaults Limit
+	hosts    map[string]Limit
+
+	mu      sync.Mutex
+	buckets map[string]*hostBucket
+}
+
+// hostBucket is the token bucket and connection slots of one host.
+type hostBucket struct {
+	limit      Limit
+	crawlDelay time.Duration
+	next       time.Time
+	conns      chan struct{}
+}
+
+// NewLimiter returns a Limiter applying defaults to every host except those
+// in hosts. A host in hosts also covers its subdomains, which share its
+// limit: "substack.com" limits all of *.substack.com together.
+func NewLimiter(defaults Limit, hosts map[string]Limit) *Limiter {
+	normalized := make(map[string]Limit, len(hosts))
+	for host, limit := range hosts {
+		normalized[strings.ToLower(strings.TrimSuffix(host, "."))] = limit
+	}
+
+	return &Limiter{
+		defaults: defaults,
+		hosts:    normalized,
+		buckets:  make(map[string]*hostBucket),
+	}
+}
+
+// Delay returns how long a request to rawURL would wait for its host's rate
+// limit if it were made now.
+func (l *Limiter) Delay(rawURL string) time.Duration {
+	bucket := l.bucket(rawURL)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return max(time.Until(bucket.next), 0)
+}
+
+// Acquire waits until a request to rawURL may start and returns a function
+// that must be called once the request is done.
+func (l *Limiter) Acquire(ctx context.Context, rawURL string) (func(), error) {
+	bucket := l.bucket(rawURL)
+
+	release := func() {}
+	if bucket.conns != nil {
+		select {
+		case bucket.conns <- struct{}{}:
+			release = func() { <-bucket.conns }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	wait := l.take(bucket)
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	return release, nil
+}
+
+// SetCrawlDelay makes requests to the host of rawURL at least delay apart,
+// as asked by the Crawl-delay of its robots.txt, when that is slower than
+// its Limit.
+func (l *Limiter) SetCrawlDelay(rawURL string, delay time.Duration) {
+	bucket := l.bucket(rawURL)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket.crawlDelay = delay
+}
+
+// take reserves the next request slot of bucket and returns how long to
+// wait for it.
+func (l *Limiter) take(bucket *hostBucket) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	spacing := bucket.crawlDelay
+	if bucket.limit.RequestsPerMinute > 0 {
+		spacing = max(spacing, time.Minute/time.Duration(bucket.limit.RequestsPerMinute))
+	}
+	if spacing <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	start := now
+	if bucket.next.After(now) {
+		start = bucket.next
+	}
+	bucket.next = start.Add(spacing)
+
+	return start.Sub(now)
+}
+
+// bucket returns the bucket of the host of rawURL, creating it on first use.
+func (l *Limiter) bucket(rawURL string) *hostBucket {
+	key, limit := l.limitFor(hostname(rawURL))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &hostBucket{limit: limit}
+		if limit.MaxConnections > 0 {
+			bucket.conns = make(chan struct{}, limit.MaxConnections)
+		}
+		l.buckets[key] = bucket
+	}
+
+	return bucket
+}
+
+// limitFor returns the key host is limited under and its limit: the most
+// specific configured domain it belongs to, or host itself with the defaults.
+func (l *Limiter) limitFor(host string) (string, Limit) {
+	for domain := host; domain != ""; {
+		if limit, ok := l.hosts[domain]; ok {
+			return domain, limit
+		}
+
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			break
+		}
+		domain = parent
+	}
+
+	return host, l.defaults
+}
+
+func hostname(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+}
@@ -0,0 +1,183 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiterSpacesRequests(t *testing.T) {
+	limiter := NewLimiter(Limit{RequestsPerMinute: 600}, nil)
+	ctx := context.Background()
+
+	start := time.Now()
+	var starts []time.Duration
+	for range 3 {
+		release, err := limiter.Acquire(ctx, "https://example.com/feed.xml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		starts = append(starts, time.Since(start))
+		release()
+	}
+
+	// 600 a minute is one every 100ms, without a burst at the start.
+	for i := 1; i < len(starts); i++ {
+		if gap := starts[i] - starts[i-1]; gap < 90*time.Millisecond {
+			t.Errorf("request %d started %s after the previous one, want 100ms", i+1, gap)
+		}
+	}
+	if delay := limiter.Delay("https://example.com/other.xml"); delay < 50*time.Millisecond || delay > 100*time.Millisecond {
+		t.Errorf("Delay() = %s, want up to 100ms", delay)
+	}
+	if delay := limiter.Delay("https://example.net/feed.xml"); delay != 0 {
+		t.Errorf("Delay() of another host = %s, want 0", delay)
+	}
+}
+
+func TestLimiterUnlimited(t *testing.T) {
+	limiter := NewLimiter(Limit{}, nil)
+
+	start := time.Now()
+	for range 100 {
+		release, err := limiter.Acquire(context.Background(), "https://example.com/feed.xml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("100 unlimited requests took %s", elapsed)
+	}
+}
+
+func TestLimiterMaxConnections(t *testing.T) {
+	limiter := NewLimiter(Limit{MaxConnections: 1}, nil)
+	ctx := context.Background()
+
+	release, err := limiter.Acquire(ctx, "https://example.com/a.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		release, err := limiter.Acquire(ctx, "https://example.com/b.xml")
+		if err == nil {
+			release()
+		}
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second request started while the first was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("second request did not start once the first was released")
+	}
+}
+
+func TestLimiterCancel(t *testing.T) {
+	limiter := NewLimiter(Limit{RequestsPerMinute: 1, MaxConnections: 1}, nil)
+
+	release, err := limiter.Acquire(context.Background(), "https://example.com/feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Waiting for a connection slot.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = limiter.Acquire(ctx, "https://example.com/feed.xml")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire() error = %v, want the context's", err)
+	}
+	release()
+
+	// Waiting for the rate limit, holding a slot that must be given back.
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = limiter.Acquire(ctx, "https://example.com/feed.xml")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire() error = %v, want the context's", err)
+	}
+	if n := len(limiter.bucket("https://example.com/feed.xml").conns); n != 0 {
+		t.Errorf("%d connection slots still taken after the cancelled request", n)
+	}
+}
+
+func TestLimitFor(t *testing.T) {
+	defaults := Limit{RequestsPerMinute: 60}
+	substack := Limit{RequestsPerMinute: 10}
+	example := Limit{MaxConnections: 2}
+	limiter := NewLimiter(defaults, map[string]Limit{
+		"substack.com":       substack,
+		"Feeds.Example.org.": example,
+	})
+
+	tests := []struct {
+		host    string
+		wantKey string
+		want    Limit
+	}{
+		{"substack.com", "substack.com", substack},
+		{"alice.substack.com", "substack.com", substack},
+		{"a.b.substack.com", "substack.com", substack},
+		{"notsubstack.com", "notsubstack.com", defaults},
+		{"feeds.example.org", "feeds.example.org", example},
+		{"eu.feeds.example.org", "feeds.example.org", example},
+		{"example.org", "example.org", defaults},
+		{"localhost", "localhost", defaults},
+	}
+
+	for _, tt := range tests {
+		key, limit := limiter.limitFor(tt.host)
+		if key != tt.wantKey || limit != tt.want {
+			t.Errorf("limitFor(%q) = %q, %+v, want %q, %+v", tt.host, key, limit, tt.wantKey, tt.want)
+		}
+	}
+
+	// Subdomains share the parent's bucket.
+	if limiter.bucket("https://alice.substack.com/feed") != limiter.bucket("https://BOB.substack.com./feed") {
+		t.Error("subdomains of substack.com have separate buckets")
+	}
+}
+
+func TestLimiterCrawlDelay(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		limit      Limit
+		crawlDelay time.Duration
+		want       time.Duration
+	}{
+		{"slower than the limit", Limit{RequestsPerMinute: 600}, 300 * time.Millisecond, 300 * time.Millisecond},
+		{"faster than the limit", Limit{RequestsPerMinute: 200}, 10 * time.Millisecond, 300 * time.Millisecond},
+		{"no limit", Limit{}, 300 * time.Millisecond, 300 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewLimiter(tt.limit, nil)
+			limiter.SetCrawlDelay("https://example.com/robots.txt", tt.crawlDelay)
+
+			release, err := limiter.Acquire(ctx, "https://example.com/feed.xml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			release()
+
+			if delay := limiter.Delay("https://example.com/feed.xml"); delay < tt.want-50*time.Millisecond || delay > tt.want {
+				t.Errorf("Delay() = %s, want about %s", delay, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package rss
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxRefreshInterval caps how long a feed's hints may postpone its next
+// fetch, so that a feed declaring a ttl of a year is still checked weekly.
+const MaxRefreshInterval = 7 * 24 * time.Hour
+
+// syndicationPeriods are the sy:updatePeriod values and their length.
+var syndicationPeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+// StatusError is returned by FetchFeed when the server answers with a status
+// other than 2xx. For 429 Too Many Requests and 503 Service Unavailable,
+// RetryAfter is the delay the server asked for in its Retry-After header, or
+// zero when it did not say.
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (err *StatusError) Error() string {
+	if err.RetryAfter > 0 {
+		return fmt.Sprintf("server returned %s, retry after %s", http.StatusText(err.StatusCode), err.RetryAfter)
+	}
+
+	return fmt.Sprintf("server returned %s", http.StatusText(err.StatusCode))
+}
+
+// RefreshInterval returns how long the feed asks clients to wait between
+// fetches, taking the longest of its ttl, its sy:updatePeriod and
+// sy:updateFrequency and the max-age of the HTTP response. It returns zero
+// when the feed gives no hint.
+func (feed *RSSFeed) RefreshInterval() time.Duration {
+	channel := feed.Channel
+
+	var interval time.Duration
+	if ttl, err := strconv.Atoi(strings.TrimSpace(channel.TTL)); err == nil && ttl > 0 {
+		interval = max(interval, time.Duration(ttl)*time.Minute)
+	}
+
+	if period, ok := syndicationPeriods[strings.ToLower(strings.TrimSpace(channel.UpdatePeriod))]; ok {
+		frequency, err := strconv.Atoi(strings.TrimSpace(channel.UpdateFrequency))
+		if err != nil || frequency < 1 {
+			frequency = 1
+		}
+		interval = max(interval, period/time.Duration(frequency))
+	}
+
+	interval = max(interval, feed.MaxAge)
+	return min(interval, MaxRefreshInterval)
+}
+
+// NextFetch returns the earliest time the feed should be fetched again after
+// being fetched at fetchedAt, waiting at least interval or RefreshInterval,
+// whichever is longer. The result is then moved past the hours and days
+// listed in skipHours and skipDays, which are in GMT.
+func (feed *RSSFeed) NextFetch(fetchedAt time.Time, interval time.Duration) time.Time {
+	next := fetchedAt.Add(max(interval, feed.RefreshInterval()))
+
+	skipHours := make(map[int]bool)
+	for _, hour := range feed.Channel.SkipHours {
+		if h, err := strconv.Atoi(strings.TrimSpace(hour)); err == nil && h >= 0 && h < 24 {
+			skipHours[h] = true
+		}
+	}
+	skipDays := make(map[time.Weekday]bool)
+	for _, day := range feed.Channel.SkipDays {
+		for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+			if strings.EqualFold(strings.TrimSpace(day), weekday.String()) {
+				skipDays[weekday] = true
+			}
+		}
+	}
+
+	// A feed that skips every hour of the week is ignored rather than never
+	// fetched again.
+	if len(skipHours) == 24 || len(skipDays) == 7 {
+		return next
+	}
+
+	for range 7 * 24 {
+		utc := next.UTC()
+		if !skipHours[utc.Hour()] && !skipDays[utc.Weekday()] {
+			break
+		}
+		next = utc.Truncate(time.Hour).Add(time.Hour)
+	}
+
+	return next
+}
+
+// maxAge returns the max-age directive of a Cache-Control header, or zero.
+func maxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(directive), "=")
+		if !ok || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+
+		seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+		if err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	return 0
+}
+
+// retryAfter parses a Retry-After header, which holds either a number of
+// seconds or an HTTP date. It returns zero when the header is missing, invalid
+// or in the past.
+func retryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(now), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,172 @@
+package rss
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// channelFeed parses an RSS feed whose channel holds elements.
+func channelFeed(t *testing.T, elements string) *RSSFeed {
+	t.Helper()
+
+	feed, err := parseFeed([]byte(`<?xml version="1.0"?>
+<rss version="2.0" xmlns:sy="http://purl.org/rss/1.0/modules/syndication/">
+<channel><title>t</title>` + elements + `</channel>
+</rss>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return feed
+}
+
+func TestRefreshInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements string
+		maxAge   time.Duration
+		want     time.Duration
+	}{
+		{"no hints", "", 0, 0},
+		{"ttl", "<ttl>60</ttl>", 0, time.Hour},
+		{"ttl with spaces", "<ttl> 90 </ttl>", 0, 90 * time.Minute},
+		{"invalid ttl", "<ttl>soon</ttl>", 0, 0},
+		{"negative ttl", "<ttl>-5</ttl>", 0, 0},
+		{"updatePeriod", "<sy:updatePeriod>daily</sy:updatePeriod>", 0, 24 * time.Hour},
+		{"updateFrequency", "<sy:updatePeriod>hourly</sy:updatePeriod><sy:updateFrequency>2</sy:updateFrequency>", 0, 30 * time.Minute},
+		{"zero updateFrequency", "<sy:updatePeriod> Weekly </sy:updatePeriod><sy:updateFrequency>0</sy:updateFrequency>", 0, 7 * 24 * time.Hour},
+		{"unknown updatePeriod", "<sy:updatePeriod>fortnightly</sy:updatePeriod>", 0, 0},
+		{"longest hint wins", "<ttl>30</ttl><sy:updatePeriod>daily</sy:updatePeriod>", time.Hour, 24 * time.Hour},
+		{"max-age", "<ttl>60</ttl>", 2 * time.Hour, 2 * time.Hour},
+		{"ttl capped", "<ttl>525600</ttl>", 0, MaxRefreshInterval},
+		{"updatePeriod capped", "<sy:updatePeriod>yearly</sy:updatePeriod>", 0, MaxRefreshInterval},
+		{"max-age capped", "", 30 * 24 * time.Hour, MaxRefreshInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := channelFeed(t, tt.elements)
+			feed.MaxAge = tt.maxAge
+			if got := feed.RefreshInterval(); got != tt.want {
+				t.Errorf("RefreshInterval() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextFetch(t *testing.T) {
+	// A Wednesday evening.
+	fetchedAt := time.Date(2024, 5, 1, 20, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		elements string
+		interval time.Duration
+		want     time.Time
+	}{
+		{"interval", "", time.Hour, time.Date(2024, 5, 1, 21, 30, 0, 0, time.UTC)},
+		{"ttl longer than interval", "<ttl>120</ttl>", time.Hour, time.Date(2024, 5, 1, 22, 30, 0, 0, time.UTC)},
+		{"interval longer than ttl", "<ttl>10</ttl>", time.Hour, time.Date(2024, 5, 1, 21, 30, 0, 0, time.UTC)},
+		{
+			"skipHours past midnight",
+			"<skipHours><hour>21</hour><hour>22</hour><hour>23</hour><hour>0</hour></skipHours>",
+			time.Hour,
+			time.Date(2024, 5, 2, 1, 0, 0, 0, time.UTC),
+		},
+		{
+			"skipHours into skipDays",
+			"<skipHours><hour>21</hour><hour>22</hour><hour>23</hour><hour>0</hour></skipHours><skipDays><day>Thursday</day></skipDays>",
+			time.Hour,
+			time.Date(2024, 5, 3, 1, 0, 0, 0, time.UTC),
+		},
+		{
+			"skipDays",
+			"<skipDays><day> wednesday </day></skipDays>",
+			time.Hour,
+			time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			"invalid hours are ignored",
+			"<skipHours><hour>24</hour><hour>-1</hour><hour>late</hour></skipHours>",
+			time.Hour,
+			time.Date(2024, 5, 1, 21, 30, 0, 0, time.UTC),
+		},
+		{
+			"every hour skipped",
+			"<skipHours>" + allHours() + "</skipHours>",
+			time.Hour,
+			time.Date(2024, 5, 1, 21, 30, 0, 0, time.UTC),
+		},
+		{
+			"every day skipped",
+			"<skipDays><day>Monday</day><day>Tuesday</day><day>Wednesday</day><day>Thursday</day><day>Friday</day><day>Saturday</day><day>Sunday</day></skipDays>",
+			time.Hour,
+			time.Date(2024, 5, 1, 21, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := channelFeed(t, tt.elements)
+			if got := feed.NextFetch(fetchedAt, tt.interval); !got.Equal(tt.want) {
+				t.Errorf("NextFetch() = %s, want %s", got.UTC(), tt.want)
+			}
+		})
+	}
+}
+
+func allHours() string {
+	var hours string
+	for hour := range 24 {
+		hours += "<hour>" + strconv.Itoa(hour) + "</hour>"
+	}
+	return hours
+}
+
+func TestMaxAge(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"max-age=300", 5 * time.Minute},
+		{`public, Max-Age="600"`, 10 * time.Minute},
+		{"no-cache, MAX-AGE=60, must-revalidate", time.Minute},
+		{"s-maxage=60", 0},
+		{"max-age=0", 0},
+		{"max-age=soon", 0},
+		{"no-store", 0},
+	}
+
+	for _, tt := range tests {
+		if got := maxAge(tt.header); got != tt.want {
+			t.Errorf("maxAge(%q) = %s, want %s", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{" 30 ", 30 * time.Second},
+		{"-5", 0},
+		{now.Add(time.Hour).Format(http.TimeFormat), time.Hour},
+		{"Wed, 01 May 2024 12:00:30 GMT", 30 * time.Second},
+		{now.Add(-time.Hour).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := retryAfter(tt.header, now); got != tt.want {
+			t.Errorf("retryAfter(%q) = %s, want %s", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package rss
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UserAgent is the product token gator sends and looks for in robots.txt.
+const UserAgent = "gator"
+
+const (
+	// robotsCacheTime is how long a host's robots.txt is trusted.
+	robotsCacheTime = 24 * time.Hour
+	// robotsMaxSize is the part of a robots.txt that is read; RFC 9309
+	// asks crawlers to parse at least 500 KiB.
+	robotsMaxSize = 500 * 1024
+)
+
+// Robots fetches, caches and evaluates the robots.txt of the hosts gator
+// visits.
+type Robots struct {
+	Client    *http.Client
+	UserAgent string
+
+	mu    sync.Mutex
+	hosts map[string]robotsEntry
+}
+
+type robotsEntry struct {
+	rules     robotsRules
+	fetchedAt time.Time
+}
+
+// robotsRules are the rules of the robots.txt group that applies to gator.
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	length  int
+	pattern *regexp.Regexp
+}
+
+// NewRobots returns a Robots with an empty cache that fetches robots.txt
+// with client, sending userAgent.
+func NewRobots(client *http.Client, userAgent string) *Robots {
+	return &Robots{
+		Client:    client,
+		UserAgent: userAgent,
+		hosts:     make(map[string]robotsEntry),
+	}
+}
+
+// Check reports whether robots.txt lets gator fetch rawURL, and the
+// Crawl-delay the host asks for. A host without a robots.txt allows
+// everything; one whose robots.txt cannot be read because of a server or
+// network error is reported as an error and asked again next time.
+func (r *Robots) Check(ctx context.Context, rawURL string) (bool, time.Duration, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return false, 0, err
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return true, 0, nil
+	}
+
+	origin := target.Scheme + "://" + target.Host
+	rules, err := r.rules(ctx, origin)
+	if err != nil {
+		return false, 0, err
+	}
+
+	path := target.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if target.RawQuery != "" {
+		path += "?" + target.RawQuery
+	}
+
+	return rules.allows(path), rules.crawlDelay, nil
+}
+
+func (r *Robots) rules(ctx context.Context, origin string) (robotsRules, error) {
+	r.mu.Lock()
+	entry, ok := r.hosts[origin]
+	r.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < robotsCacheTime {
+		return entry.rules, nil
+	}
+
+	rules, err := r.fetch(ctx, origin)
+	if err != nil {
+		return robotsRules{}, err
+	}
+
+	r.mu.Lock()
+	r.hosts[origin] = robotsEntry{rules: rules, fetchedAt: time.Now()}
+	r.mu.Unlock()
+
+	return rules, nil
+}
+
+func (r *Robots) fetch(ctx context.Context, origin string) (robotsRules, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", origin+"/robots.txt", nil)
+	if err != nil {
+		return robotsRules{}, err
+	}
+
+	req.Header.Set("User-Agent", r.UserAgent)
+
+	res, err := r.Client.Do(req)
+	if err != nil {
+		return robotsRules{}, fmt.Errorf("couldn't read robots.txt: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode >= 500:
+		return robotsRules{}, fmt.Errorf("couldn't read robots.txt: server returned %s", res.Status)
+	case res.StatusCode >= 400:
+		// No robots.txt, so nothing is disallowed.
+		return robotsRules{}, nil
+	}
+
+	return parseRobots(io.LimitReader(res.Body, robotsMaxSize), UserAgent), nil
+}
+
+// parseRobots reads the rules of the groups for agent, or of the "*" groups
+// when none names agent. Lines gator does not understand are ignored.
+func parseRobots(r io.Reader, agent string) robotsRules {
+	var agentRules, anyRules robotsRules
+	var forAgent, forAny, inAgents, sawAgent bool
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "user-agent" {
+			// Consecutive user-agent lines share the group that follows.
+			if !inAgents {
+				forAgent, forAny = false, false
+			}
+			inAgents = true
+
+			name := strings.ToLower(value)
+			forAgent = forAgent || name == strings.ToLower(agent)
+			forAny = forAny || name == "*"
+			sawAgent = sawAgent || forAgent
+			continue
+		}
+		inAgents = false
+
+		var group *robotsRules
+		switch {
+		case forAgent:
+			group = &agentRules
+		case forAny:
+			group = &anyRules
+		default:
+			continue
+		}
+
+		switch key {
+		case "allow", "disallow":
+			if value == "" {
+				continue
+			}
+			group.rules = append(group.rules, robotsRule{
+				allow:   key == "allow",
+				length:  len(value),
+				pattern: robotsPattern(value),
+			})
+		case "crawl-delay":
+			seconds, err := strconv.ParseFloat(value, 64)
+			if err == nil && seconds > 0 {
+				group.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+
+	// A group naming agent wins even when it allows everything, as in
+	// "User-agent: gator" followed by an empty "Disallow:".
+	if sawAgent {
+		return agentRules
+	}
+
+	return anyRules
+}
+
+// robotsPattern compiles a robots.txt path pattern, in which * matches any
+// characters and a trailing $ anchors the end of the path.
+func robotsPattern(value string) *regexp.Regexp {
+	anchored := strings.HasSuffix(value, "$")
+	value = strings.TrimSuffix(value, "$")
+
+	parts := strings.Split(value, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+
+	return regexp.MustCompile(expr)
+}
+
+// allows applies the most specific matching rule to path, preferring allow
+// when an allow and a disallow rule are equally specific.
+func (rules robotsRules) allows(path string) bool {
+	if path == "/robots.txt" {
+		return true
+	}
+
+	allowed := true
+	longest := -1
+	for _, rule := range rules.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > longest || (rule.length == longest && rule.allow) {
+			allowed = rule.allow
+			longest = rule.length
+		}
+	}
+
+	return allowed
+}
@@ -0,0 +1,195 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRobots(t *testing.T) {
+	tests := []struct {
+		name       string
+		robots     string
+		allowed    []string
+		disallowed []string
+		crawlDelay time.Duration
+	}{
+		{
+			name:       "empty file allows everything",
+			robots:     "",
+			allowed:    []string{"/", "/feed.xml"},
+			disallowed: nil,
+		},
+		{
+			name:       "any group",
+			robots:     "User-agent: *\nDisallow: /private\n",
+			allowed:    []string{"/feed.xml", "/robots.txt"},
+			disallowed: []string{"/private", "/private/feed.xml"},
+		},
+		{
+			name:       "gator group wins over any",
+			robots:     "User-agent: *\nDisallow: /\n\nUser-agent: Gator\nDisallow: /drafts\n",
+			allowed:    []string{"/feed.xml"},
+			disallowed: []string{"/drafts/1"},
+		},
+		{
+			name:       "empty disallow for gator allows only gator",
+			robots:     "User-agent: gator\nDisallow:\n\nUser-agent: *\nDisallow: /\n",
+			allowed:    []string{"/", "/feed"},
+			disallowed: nil,
+		},
+		{
+			name:       "other agents are ignored",
+			robots:     "User-agent: otherbot\nDisallow: /\n",
+			allowed:    []string{"/feed"},
+			disallowed: nil,
+		},
+		{
+			name:       "consecutive user-agent lines share a group",
+			robots:     "User-agent: otherbot\nUser-agent: gator\nDisallow: /shared\n\nUser-agent: otherbot\nDisallow: /other\n",
+			allowed:    []string{"/other"},
+			disallowed: []string{"/shared"},
+		},
+		{
+			name:       "groups for gator are merged",
+			robots:     "User-agent: gator\nDisallow: /a\n\nUser-agent: *\nDisallow: /b\n\nUser-agent: gator\nDisallow: /c\n",
+			allowed:    []string{"/b"},
+			disallowed: []string{"/a", "/c"},
+		},
+		{
+			name:       "wildcard",
+			robots:     "User-agent: *\nDisallow: /*.php\n",
+			allowed:    []string{"/feed.xml"},
+			disallowed: []string{"/index.php", "/a/b.php?x=1"},
+		},
+		{
+			name:       "end anchor",
+			robots:     "User-agent: *\nDisallow: /*.xml$\n",
+			allowed:    []string{"/feed.xml?page=2", "/feed.xmls"},
+			disallowed: []string{"/feed.xml"},
+		},
+		{
+			name:       "longest match wins",
+			robots:     "User-agent: *\nDisallow: /feeds\nAllow: /feeds/public\n",
+			allowed:    []string{"/feeds/public/rss"},
+			disallowed: []string{"/feeds/private"},
+		},
+		{
+			name:       "allow wins a tie",
+			robots:     "User-agent: *\nDisallow: /feed\nAllow: /feed\n",
+			allowed:    []string{"/feed"},
+			disallowed: nil,
+		},
+		{
+			name:       "comments and unknown lines",
+			robots:     "# hello\nUser-agent: * # everyone\nSitemap: https://example.com/sitemap.xml\nDisallow: /tmp # scratch\n",
+			allowed:    []string{"/feed"},
+			disallowed: []string{"/tmp/x"},
+		},
+		{
+			name:       "crawl-delay",
+			robots:     "User-agent: *\nCrawl-delay: 1.5\n",
+			allowed:    []string{"/feed"},
+			crawlDelay: 1500 * time.Millisecond,
+		},
+		{
+			name:       "crawl-delay of the gator group",
+			robots:     "User-agent: *\nCrawl-delay: 30\n\nUser-agent: gator\nCrawl-delay: 5\n",
+			allowed:    []string{"/feed"},
+			crawlDelay: 5 * time.Second,
+		},
+		{
+			name:    "invalid crawl-delay is ignored",
+			robots:  "User-agent: *\nCrawl-delay: soon\n",
+			allowed: []string{"/feed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := parseRobots(strings.NewReader(tt.robots), UserAgent)
+			for _, path := range tt.allowed {
+				if !rules.allows(path) {
+					t.Errorf("allows(%q) = false, want true", path)
+				}
+			}
+			for _, path := range tt.disallowed {
+				if rules.allows(path) {
+					t.Errorf("allows(%q) = true, want false", path)
+				}
+			}
+			if rules.crawlDelay != tt.crawlDelay {
+				t.Errorf("crawlDelay = %s, want %s", rules.crawlDelay, tt.crawlDelay)
+			}
+		})
+	}
+}
+
+func TestRobotsCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		allowed bool
+		wantErr bool
+	}{
+		{"robots.txt", http.StatusOK, "User-agent: *\nDisallow: /feed\n", false, false},
+		{"missing robots.txt allows everything", http.StatusNotFound, "User-agent: *\nDisallow: /\n", true, false},
+		{"forbidden robots.txt allows everything", http.StatusForbidden, "", true, false},
+		{"server error", http.StatusServiceUnavailable, "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var userAgent string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userAgent = r.Header.Get("User-Agent")
+				if r.URL.Path != "/robots.txt" {
+					http.NotFound(w, r)
+					return
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			robots := NewRobots(server.Client(), "gator/test")
+			allowed, _, err := robots.Check(context.Background(), server.URL+"/feed")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, want error %v", err, tt.wantErr)
+			}
+			if allowed != tt.allowed {
+				t.Errorf("Check() = %v, want %v", allowed, tt.allowed)
+			}
+			if userAgent != "gator/test" {
+				t.Errorf("robots.txt requested as %q", userAgent)
+			}
+		})
+	}
+}
+
+func TestRobotsCheckCaches(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("User-agent: *\nCrawl-delay: 2\n"))
+	}))
+	defer server.Close()
+
+	robots := NewRobots(server.Client(), "gator/test")
+	for range 3 {
+		_, delay, err := robots.Check(context.Background(), server.URL+"/feed")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if delay != 2*time.Second {
+			t.Errorf("Check() crawl delay = %s, want 2s", delay)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("robots.txt fetched %d times, want once", requests)
+	}
+}
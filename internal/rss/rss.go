@@ -1,53 +1,102 @@
 package rss
 
 import (
-	"context"
 	"encoding/xml"
 	"html"
-	"io"
-	"net/http"
+	"strings"
+	"time"
 )
 
 type RSSFeed struct {
 	Channel struct {
-		Title       string    `xml:"title"`
-		Link        string    `xml:"link"`
-		Description string    `xml:"description"`
-		Item        []RSSItem `xml:"item"`
+		Title           string      `xml:"title"`
+		Link            string      `xml:"link"`
+		Description     string      `xml:"description"`
+		ITunesAuthor    string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+		ITunesType      string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd type"`
+		ITunesImage     ITunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+		TTL             string      `xml:"ttl"`
+		SkipHours       []string    `xml:"skipHours>hour"`
+		SkipDays        []string    `xml:"skipDays>day"`
+		UpdatePeriod    string      `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+		UpdateFrequency string      `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+		Item            []RSSItem   `xml:"item"`
 	} `xml:"channel"`
+
+	// MaxAge is the max-age of the response's Cache-Control header.
+	MaxAge time.Duration `xml:"-"`
 }
 
 type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	Title          string           `xml:"title"`
+	Link           string           `xml:"link"`
+	Description    string           `xml:"description"`
+	PubDate        string           `xml:"pubDate"`
+	DCDate         string           `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Author         string           `xml:"author"`
+	DCCreator      string           `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	ITunesAuthor   string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+	Categories     []string         `xml:"category"`
+	Enclosures     []RSSEnclosure   `xml:"enclosure"`
+	ITunesDuration string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ITunesImage    ITunesImage      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	MediaContent   []MediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaThumbnail []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
 }
 
-func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
-	client := &http.Client{}
-
-	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
-	if err != nil {
-		return nil, err
+// PublishedDate returns the raw publication date of the item, preferring
+// pubDate and falling back to dc:date.
+func (item RSSItem) PublishedDate() string {
+	if item.PubDate != "" {
+		return item.PubDate
 	}
 
-	req.Header.Set("User-Agent", "gator")
+	return item.DCDate
+}
 
-	res, err := client.Do(req)
-	if err != nil {
-		return nil, err
+// AuthorName returns the author of the item, preferring dc:creator over the
+// RSS author element, which usually holds an email address optionally
+// followed by the author's name in parentheses.
+func (item RSSItem) AuthorName() string {
+	if creator := strings.TrimSpace(item.DCCreator); creator != "" {
+		return creator
 	}
 
-	defer res.Body.Close()
+	author := strings.TrimSpace(item.Author)
+	if open := strings.Index(author, "("); open >= 0 && strings.HasSuffix(author, ")") {
+		if name := strings.TrimSpace(author[open+1 : len(author)-1]); name != "" {
+			return name
+		}
+	}
+	if author != "" {
+		return author
+	}
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
+	return strings.TrimSpace(item.ITunesAuthor)
+}
+
+// CategoryNames returns the trimmed, de-duplicated categories of the item.
+func (item RSSItem) CategoryNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, category := range item.Categories {
+		name := strings.TrimSpace(category)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		names = append(names, name)
 	}
 
+	return names
+}
+
+// parseFeed decodes an RSS document and unescapes the HTML entities feeds
+// put in titles, descriptions and categories.
+func parseFeed(body []byte) (*RSSFeed, error) {
 	var feed RSSFeed
-	err = xml.Unmarshal(body, &feed)
+	err := xml.Unmarshal(body, &feed)
 	if err != nil {
 		return nil, err
 	}
@@ -58,6 +107,9 @@ func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
 	for ind, it := range feed.Channel.Item {
 		feed.Channel.Item[ind].Description = html.UnescapeString(it.Description)
 		feed.Channel.Item[ind].Title = html.UnescapeString(it.Title)
+		for categoryInd, category := range it.Categories {
+			feed.Channel.Item[ind].Categories[categoryInd] = html.UnescapeString(category)
+		}
 	}
 
 	return &feed, nil
@@ -0,0 +1,707 @@
+package storage
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/google/uuid"
+)
+
+// Memory is a Store that keeps everything in process and forgets it when
+// the program exits. It behaves like the SQL backends for the queries in
+// Store: missing rows are sql.ErrNoRows, unique violations are ErrDuplicate
+// and deletes cascade. Folders, filter rules and webhooks live outside Store,
+// so users have none of them and filtering posts by folder is
+// ErrNotSupported.
+type Memory struct {
+	mu         sync.Mutex
+	users      []database.User
+	feeds      []database.Feed
+	follows    []database.FeedFollow
+	posts      []database.Post
+	categories []database.PostCategory
+}
+
+var _ Store = (*Memory)(nil)
+
+// NewMemory returns an empty in-memory store.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, user := range m.users {
+		if user.ID == arg.ID || user.Name == arg.Name {
+			return database.User{}, fmt.Errorf("user %s: %w", arg.Name, ErrDuplicate)
+		}
+	}
+
+	user := database.User{
+		ID:        arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		Name:      arg.Name,
+		Role:      arg.Role,
+	}
+	m.users = append(m.users, user)
+	return user, nil
+}
+
+func (m *Memory) GetUser(ctx context.Context, name string) (database.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, user := range m.users {
+		if user.Name == name {
+			return user, nil
+		}
+	}
+
+	return database.User{}, sql.ErrNoRows
+}
+
+func (m *Memory) GetUsers(ctx context.Context) ([]database.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.users), nil
+}
+
+func (m *Memory) RenameUser(ctx context.Context, arg database.RenameUserParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := slices.IndexFunc(m.users, func(user database.User) bool { return user.Name == arg.OldName })
+	if index < 0 {
+		return 0, nil
+	}
+
+	if arg.NewName != arg.OldName && slices.ContainsFunc(m.users, func(user database.User) bool { return user.Name == arg.NewName }) {
+		return 0, fmt.Errorf("user %s: %w", arg.NewName, ErrDuplicate)
+	}
+
+	m.users[index].Name = arg.NewName
+	m.users[index].UpdatedAt = time.Now()
+	return 1, nil
+}
+
+func (m *Memory) DeleteUserByName(ctx context.Context, name string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := slices.IndexFunc(m.users, func(user database.User) bool { return user.Name == name })
+	if index < 0 {
+		return 0, nil
+	}
+
+	id := m.users[index].ID
+	m.users = slices.Delete(m.users, index, index+1)
+	m.follows = slices.DeleteFunc(m.follows, func(follow database.FeedFollow) bool { return follow.UserID == id })
+	for i := range m.feeds {
+		if m.feeds[i].AddedBy.Valid && m.feeds[i].AddedBy.UUID == id {
+			m.feeds[i].AddedBy = uuid.NullUUID{}
+		}
+	}
+	return 1, nil
+}
+
+func (m *Memory) DeleteUser(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.users = nil
+	m.follows = nil
+	for i := range m.feeds {
+		m.feeds[i].AddedBy = uuid.NullUUID{}
+	}
+	return nil
+}
+
+func (m *Memory) SetUserRole(ctx context.Context, arg database.SetUserRoleParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := slices.IndexFunc(m.users, func(user database.User) bool { return user.Name == arg.Name })
+	if index < 0 {
+		return 0, nil
+	}
+
+	m.users[index].Role = arg.Role
+	m.users[index].UpdatedAt = time.Now()
+	return 1, nil
+}
+
+func (m *Memory) CountUsersWithRole(ctx context.Context, role string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var count int64
+	for _, user := range m.users {
+		if user.Role == role {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// GetUserStats counts the user's follows and the posts of the feeds they
+// follow. Folders, filter rules and webhooks are always zero.
+func (m *Memory) GetUserStats(ctx context.Context, arg database.GetUserStatsParams) (database.GetUserStatsRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var stats database.GetUserStatsRow
+	for _, follow := range m.follows {
+		if follow.UserID == arg.UserID {
+			stats.Follows++
+		}
+	}
+	for _, post := range m.posts {
+		if !m.isFollowing(arg.UserID, post.FeedID) {
+			continue
+		}
+		stats.Posts++
+		if !post.CreatedAt.Before(arg.Since) {
+			stats.RecentPosts++
+		}
+	}
+
+	return stats, nil
+}
+
+func (m *Memory) CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, feed := range m.feeds {
+		if feed.ID == arg.ID || feed.Url == arg.Url {
+			return database.Feed{}, fmt.Errorf("feed %s: %w", arg.Url, ErrDuplicate)
+		}
+	}
+
+	feed := database.Feed{
+		ID:        arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		Name:      arg.Name,
+		Url:       arg.Url,
+		AddedBy:   arg.AddedBy,
+	}
+	m.feeds = append(m.feeds, feed)
+	return feed, nil
+}
+
+func (m *Memory) GetFeed(ctx context.Context, url string) (database.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.feedIndex(func(feed database.Feed) bool { return feed.Url == url })
+	if index < 0 {
+		return database.Feed{}, sql.ErrNoRows
+	}
+
+	return m.feeds[index], nil
+}
+
+func (m *Memory) GetFeeds(ctx context.Context) ([]database.GetFeedsRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var rows []database.GetFeedsRow
+	for _, feed := range m.feeds {
+		row := database.GetFeedsRow{
+			FeedName:        feed.Name,
+			Url:             feed.Url,
+			IsPodcast:       feed.IsPodcast,
+			LastFetchedAt:   feed.LastFetchedAt,
+			LastFetchStatus: feed.LastFetchStatus,
+		}
+		if feed.AddedBy.Valid {
+			if index := m.userIndex(feed.AddedBy.UUID); index >= 0 {
+				row.AddedByName = sql.NullString{String: m.users[index].Name, Valid: true}
+			}
+		}
+		for _, follow := range m.follows {
+			if follow.FeedID == feed.ID {
+				row.Followers++
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (m *Memory) GetFeedByUrl(ctx context.Context, url string) (database.GetFeedByUrlRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.feedIndex(func(feed database.Feed) bool { return feed.Url == url })
+	if index < 0 {
+		return database.GetFeedByUrlRow{}, sql.ErrNoRows
+	}
+
+	return database.GetFeedByUrlRow{ID: m.feeds[index].ID, FeedName: m.feeds[index].Name}, nil
+}
+
+func (m *Memory) GetFeedStats(ctx context.Context, feedID uuid.UUID) (database.GetFeedStatsRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var stats database.GetFeedStatsRow
+	for _, follow := range m.follows {
+		if follow.FeedID == feedID {
+			stats.Followers++
+		}
+	}
+	for _, post := range m.posts {
+		if post.FeedID == feedID {
+			stats.Posts++
+		}
+	}
+
+	return stats, nil
+}
+
+func (m *Memory) RenameFeed(ctx context.Context, arg database.RenameFeedParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.feedIndex(func(feed database.Feed) bool { return feed.Url == arg.Url })
+	if index < 0 {
+		return 0, nil
+	}
+
+	m.feeds[index].Name = arg.Name
+	m.feeds[index].UpdatedAt = time.Now()
+	return 1, nil
+}
+
+func (m *Memory) SetFeedUrl(ctx context.Context, arg database.SetFeedUrlParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.feedIndex(func(feed database.Feed) bool { return feed.Url == arg.OldUrl })
+	if index < 0 {
+		return 0, nil
+	}
+
+	if arg.NewUrl != arg.OldUrl && m.feedIndex(func(feed database.Feed) bool { return feed.Url == arg.NewUrl }) >= 0 {
+		return 0, fmt.Errorf("feed %s: %w", arg.NewUrl, ErrDuplicate)
+	}
+
+	m.feeds[index].Url = arg.NewUrl
+	m.feeds[index].UpdatedAt = time.Now()
+	return 1, nil
+}
+
+func (m *Memory) DeleteFeedByUrl(ctx context.Context, url string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.feedIndex(func(feed database.Feed) bool { return feed.Url == url })
+	if index < 0 {
+		return 0, nil
+	}
+
+	id := m.feeds[index].ID
+	m.feeds = slices.Delete(m.feeds, index, index+1)
+	m.follows = slices.DeleteFunc(m.follows, func(follow database.FeedFollow) bool { return follow.FeedID == id })
+
+	var deletedPosts []uuid.UUID
+	m.posts = slices.DeleteFunc(m.posts, func(post database.Post) bool {
+		if post.FeedID == id {
+			deletedPosts = append(deletedPosts, post.ID)
+			return true
+		}
+		return false
+	})
+	m.categories = slices.DeleteFunc(m.categories, func(category database.PostCategory) bool {
+		return slices.Contains(deletedPosts, category.PostID)
+	})
+	return 1, nil
+}
+
+// GetNextFeedToFetch returns the feed that has been due the longest at
+// nextFetchAt, preferring feeds that were never scheduled.
+func (m *Memory) GetNextFeedToFetch(ctx context.Context, nextFetchAt sql.NullTime) (database.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []database.Feed
+	for _, feed := range m.feeds {
+		if !feed.NextFetchAt.Valid || !feed.NextFetchAt.Time.After(nextFetchAt.Time) {
+			due = append(due, feed)
+		}
+	}
+	if len(due) == 0 {
+		return database.Feed{}, sql.ErrNoRows
+	}
+
+	return slices.MinFunc(due, func(a, b database.Feed) int {
+		return compareNullTime(a.NextFetchAt, b.NextFetchAt)
+	}), nil
+}
+
+// GetEarliestNextFetch returns the soonest time a scheduled feed is due, or
+// sql.ErrNoRows when no feed is scheduled.
+func (m *Memory) GetEarliestNextFetch(ctx context.Context) (sql.NullTime, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var earliest sql.NullTime
+	for _, feed := range m.feeds {
+		if feed.NextFetchAt.Valid && (!earliest.Valid || feed.NextFetchAt.Time.Before(earliest.Time)) {
+			earliest = feed.NextFetchAt
+		}
+	}
+	if !earliest.Valid {
+		return sql.NullTime{}, sql.ErrNoRows
+	}
+
+	return earliest, nil
+}
+
+// GetFeedPublishTimes returns the publication times of the newest dated posts
+// of a feed, newest first.
+func (m *Memory) GetFeedPublishTimes(ctx context.Context, arg database.GetFeedPublishTimesParams) ([]sql.NullTime, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var times []sql.NullTime
+	for _, post := range m.posts {
+		if post.FeedID == arg.FeedID && post.PublishedAt.Valid && !post.PublishedAt.Time.Equal(post.CreatedAt) {
+			times = append(times, post.PublishedAt)
+		}
+	}
+	slices.SortFunc(times, func(a, b sql.NullTime) int {
+		return compareNullTime(b, a)
+	})
+
+	return times[:min(len(times), int(arg.Limit))], nil
+}
+
+func (m *Memory) PostponeFeedFetch(ctx context.Context, arg database.PostponeFeedFetchParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.feedIndex(func(feed database.Feed) bool { return feed.ID == arg.ID })
+	if index >= 0 {
+		m.feeds[index].NextFetchAt = arg.NextFetchAt
+	}
+
+	return nil
+}
+
+func (m *Memory) SetFeedFetchInterval(ctx context.Context, arg database.SetFeedFetchIntervalParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.feedIndex(func(feed database.Feed) bool { return feed.Url == arg.Url })
+	if index < 0 {
+		return 0, nil
+	}
+
+	m.feeds[index].FetchIntervalSeconds = arg.FetchIntervalSeconds
+	m.feeds[index].NextFetchAt = sql.NullTime{}
+	m.feeds[index].UpdatedAt = time.Now()
+	return 1, nil
+}
+
+func (m *Memory) MarkFeedFetched(ctx context.Context, id uuid.UUID) (database.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.feedIndex(func(feed database.Feed) bool { return feed.ID == id })
+	if index < 0 {
+		return database.Feed{}, sql.ErrNoRows
+	}
+
+	now := time.Now()
+	m.feeds[index].LastFetchedAt = sql.NullTime{Time: now, Valid: true}
+	m.feeds[index].UpdatedAt = now
+	return m.feeds[index], nil
+}
+
+func (m *Memory) RecordFeedFetch(ctx context.Context, arg database.RecordFeedFetchParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.feedIndex(func(feed database.Feed) bool { return feed.ID == arg.ID })
+	if index >= 0 {
+		m.feeds[index].LastFetchStatus = arg.LastFetchStatus
+		m.feeds[index].LastFetchError = arg.LastFetchError
+		m.feeds[index].LastFetchNewPosts = arg.LastFetchNewPosts
+		m.feeds[index].NextFetchAt = arg.NextFetchAt
+		m.feeds[index].UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+func (m *Memory) SetFeedIsPodcast(ctx context.Context, arg database.SetFeedIsPodcastParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.feedIndex(func(feed database.Feed) bool { return feed.ID == arg.ID })
+	if index >= 0 {
+		m.feeds[index].IsPodcast = arg.IsPodcast
+		m.feeds[index].UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+func (m *Memory) SetFeedAutoDownload(ctx context.Context, arg database.SetFeedAutoDownloadParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.feedIndex(func(feed database.Feed) bool { return feed.Url == arg.Url })
+	if index < 0 {
+		return 0, nil
+	}
+
+	m.feeds[index].AutoDownload = arg.AutoDownload
+	m.feeds[index].UpdatedAt = time.Now()
+	return 1, nil
+}
+
+func (m *Memory) DeleteFeeds(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.feeds = nil
+	m.follows = nil
+	m.posts = nil
+	m.categories = nil
+	return nil
+}
+
+func (m *Memory) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	userIndex := m.userIndex(arg.UserID)
+	feedIndex := m.feedIndex(func(feed database.Feed) bool { return feed.ID == arg.FeedID })
+	if userIndex < 0 || feedIndex < 0 {
+		return database.CreateFeedFollowRow{}, fmt.Errorf("feed follow references a missing user or feed")
+	}
+
+	for _, follow := range m.follows {
+		if follow.ID == arg.ID || (follow.UserID == arg.UserID && follow.FeedID == arg.FeedID) {
+			return database.CreateFeedFollowRow{}, fmt.Errorf("feed follow: %w", ErrDuplicate)
+		}
+	}
+
+	follow := database.FeedFollow{
+		ID:        arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		UserID:    arg.UserID,
+		FeedID:    arg.FeedID,
+	}
+	m.follows = append(m.follows, follow)
+
+	return database.CreateFeedFollowRow{
+		ID:        follow.ID,
+		CreatedAt: follow.CreatedAt,
+		UpdatedAt: follow.UpdatedAt,
+		UserID:    follow.UserID,
+		FeedID:    follow.FeedID,
+		FeedName:  m.feeds[feedIndex].Name,
+		UserName:  m.users[userIndex].Name,
+	}, nil
+}
+
+func (m *Memory) GetFeedFollowsForUser(ctx context.Context, name string) ([]database.GetFeedFollowsForUserRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var rows []database.GetFeedFollowsForUserRow
+	for _, user := range m.users {
+		if user.Name != name {
+			continue
+		}
+
+		for _, follow := range m.follows {
+			if follow.UserID != user.ID {
+				continue
+			}
+
+			feed := m.feeds[m.feedIndex(func(feed database.Feed) bool { return feed.ID == follow.FeedID })]
+			rows = append(rows, database.GetFeedFollowsForUserRow{
+				UserName: user.Name,
+				FeedName: feed.Name,
+				UserID:   user.ID,
+				FeedID:   feed.ID,
+				FeedUrl:  feed.Url,
+			})
+		}
+	}
+
+	slices.SortStableFunc(rows, func(a, b database.GetFeedFollowsForUserRow) int {
+		return strings.Compare(a.FeedName, b.FeedName)
+	})
+	return rows, nil
+}
+
+func (m *Memory) DeleteFeedFollowByUrl(ctx context.Context, arg database.DeleteFeedFollowByUrlParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.follows = slices.DeleteFunc(m.follows, func(follow database.FeedFollow) bool {
+		if follow.UserID != arg.UserID {
+			return false
+		}
+
+		index := m.feedIndex(func(feed database.Feed) bool { return feed.ID == follow.FeedID })
+		return m.feeds[index].Url == arg.Url
+	})
+	return nil
+}
+
+func (m *Memory) CreatePost(ctx context.Context, arg database.CreatePostParams) (database.Post, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.feedIndex(func(feed database.Feed) bool { return feed.ID == arg.FeedID }) < 0 {
+		return database.Post{}, fmt.Errorf("post %s references a missing feed", arg.Url)
+	}
+
+	for _, post := range m.posts {
+		if post.ID == arg.ID || post.Url == arg.Url {
+			return database.Post{}, fmt.Errorf("post %s: %w", arg.Url, ErrDuplicate)
+		}
+	}
+
+	post := database.Post(arg)
+	m.posts = append(m.posts, post)
+	return post, nil
+}
+
+func (m *Memory) CreatePostCategory(ctx context.Context, arg database.CreatePostCategoryParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	category := database.PostCategory(arg)
+	if !slices.Contains(m.categories, category) {
+		m.categories = append(m.categories, category)
+	}
+
+	return nil
+}
+
+// GetPostsForUser lists the newest posts of the feeds the user follows.
+// Memory has no folders, so a folder filter is ErrNotSupported.
+func (m *Memory) GetPostsForUser(ctx context.Context, arg database.GetPostsForUserParams) ([]database.Post, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if arg.Folder != "" {
+		return nil, fmt.Errorf("folder %s: %w", arg.Folder, ErrNotSupported)
+	}
+
+	var posts []database.Post
+	for _, post := range m.posts {
+		if !m.isFollowing(arg.ID, post.FeedID) {
+			continue
+		}
+
+		if arg.FeedUrl != "" {
+			index := m.feedIndex(func(feed database.Feed) bool { return feed.ID == post.FeedID })
+			if m.feeds[index].Url != arg.FeedUrl {
+				continue
+			}
+		}
+
+		if arg.Author != "" && !(post.Author.Valid && strings.EqualFold(post.Author.String, arg.Author)) {
+			continue
+		}
+
+		if arg.Category != "" && !slices.ContainsFunc(m.categories, func(category database.PostCategory) bool {
+			return category.PostID == post.ID && strings.EqualFold(category.Name, arg.Category)
+		}) {
+			continue
+		}
+
+		posts = append(posts, post)
+	}
+
+	// Like Postgres, descending order puts posts without a date first.
+	slices.SortStableFunc(posts, func(a, b database.Post) int {
+		return compareNullTime(b.PublishedAt, a.PublishedAt)
+	})
+	if len(posts) > int(arg.LimitCount) {
+		posts = posts[:max(arg.LimitCount, 0)]
+	}
+
+	return posts, nil
+}
+
+func (m *Memory) GetTopCategoriesForUser(ctx context.Context, arg database.GetTopCategoriesForUserParams) ([]database.GetTopCategoriesForUserRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := make(map[string]int64)
+	for _, category := range m.categories {
+		index := slices.IndexFunc(m.posts, func(post database.Post) bool { return post.ID == category.PostID })
+		if index < 0 || !m.isFollowing(arg.UserID, m.posts[index].FeedID) {
+			continue
+		}
+		counts[strings.ToLower(category.Name)]++
+	}
+
+	var rows []database.GetTopCategoriesForUserRow
+	for name, count := range counts {
+		rows = append(rows, database.GetTopCategoriesForUserRow{Name: name, PostCount: count})
+	}
+
+	slices.SortFunc(rows, func(a, b database.GetTopCategoriesForUserRow) int {
+		return cmp.Or(cmp.Compare(b.PostCount, a.PostCount), strings.Compare(a.Name, b.Name))
+	})
+	if len(rows) > int(arg.Limit) {
+		rows = rows[:max(arg.Limit, 0)]
+	}
+
+	return rows, nil
+}
+
+func (m *Memory) userIndex(id uuid.UUID) int {
+	return slices.IndexFunc(m.users, func(user database.User) bool { return user.ID == id })
+}
+
+func (m *Memory) feedIndex(match func(database.Feed) bool) int {
+	return slices.IndexFunc(m.feeds, match)
+}
+
+func (m *Memory) isFollowing(userID, feedID uuid.UUID) bool {
+	return slices.ContainsFunc(m.follows, func(follow database.FeedFollow) bool {
+		return follow.UserID == userID && follow.FeedID == feedID
+	})
+}
+
+// compareNullTime orders NULL before any time, like ORDER BY ... ASC NULLS
+// FIRST.
+func compareNullTime(a, b sql.NullTime) int {
+	switch {
+	case !a.Valid && !b.Valid:
+		return 0
+	case !a.Valid:
+		return -1
+	case !b.Valid:
+		return 1
+	default:
+		return a.Time.Compare(b.Time)
+	}
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ctiller15/gator/internal/database"
+	"modernc.org/sqlite"
+)
+
+// sqlitePragmas are run on every new connection. Foreign keys are off by
+// default in SQLite, and the busy timeout lets agg and other commands share
+// the file.
+var sqlitePragmas = []string{
+	"foreign_keys(1)",
+	"busy_timeout(5000)",
+	"journal_mode(WAL)",
+}
+
+var registerFunctions sync.Once
+
+func openSQLite(path string) (*DB, error) {
+	path = strings.TrimPrefix(path, "//")
+	if path == "" {
+		return nil, fmt.Errorf("sqlite db_url needs a file name, e.g. sqlite:///home/me/gator.db")
+	}
+
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		homedir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(homedir, rest)
+	}
+
+	if path != ":memory:" {
+		err := os.MkdirAll(filepath.Dir(path), 0700)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	registerFunctions.Do(func() {
+		err = registerSQLiteFunctions()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	for _, pragma := range sqlitePragmas {
+		query.Add("_pragma", pragma)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	if path == ":memory:" {
+		// Every connection to :memory: is a separate database.
+		db.SetMaxOpenConns(1)
+	}
+
+	return &DB{DB: db, Dialect: SQLite}, nil
+}
+
+// registerSQLiteFunctions adds the Postgres functions used by the queries
+// that SQLite lacks.
+func registerSQLiteFunctions() error {
+	var patterns sync.Map
+
+	err := sqlite.RegisterDeterministicScalarFunction("regexp_like", 3, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+
+		value, pattern, flags := textArg(args[0]), textArg(args[1]), textArg(args[2])
+		if strings.Contains(flags, "i") {
+			pattern = "(?i)" + pattern
+		}
+
+		compiled, ok := patterns.Load(pattern)
+		if !ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, err
+			}
+			compiled, _ = patterns.LoadOrStore(pattern, re)
+		}
+
+		return compiled.(*regexp.Regexp).MatchString(value), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return sqlite.RegisterDeterministicScalarFunction("strpos", 2, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+
+		value, substring := textArg(args[0]), textArg(args[1])
+		index := strings.Index(value, substring)
+		if index < 0 {
+			return int64(0), nil
+		}
+
+		return int64(utf8.RuneCountInString(value[:index]) + 1), nil
+	})
+}
+
+func textArg(value driver.Value) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// utcArgs converts time arguments to UTC before passing them to SQLite,
+// which stores timestamps as text and compares them as strings.
+type utcArgs struct {
+	database.DBTX
+}
+
+func (u utcArgs) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return u.DBTX.ExecContext(ctx, query, toUTC(args)...)
+}
+
+func (u utcArgs) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return u.DBTX.PrepareContext(ctx, query)
+}
+
+func (u utcArgs) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return u.DBTX.QueryContext(ctx, query, toUTC(args)...)
+}
+
+func (u utcArgs) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return u.DBTX.QueryRowContext(ctx, query, toUTC(args)...)
+}
+
+func toUTC(args []interface{}) []interface{} {
+	converted := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case time.Time:
+			converted[i] = arg.UTC()
+		case sql.NullTime:
+			arg.Time = arg.Time.UTC()
+			converted[i] = arg
+		default:
+			converted[i] = arg
+		}
+	}
+
+	return converted
+}
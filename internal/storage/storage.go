@@ -0,0 +1,96 @@
+// Package storage opens the database named by the db_url setting, which may
+// be a Postgres server or a local SQLite file.
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Dialect is the SQL database behind a DB.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// DB is an open database together with its dialect.
+type DB struct {
+	*sql.DB
+	Dialect Dialect
+}
+
+// Open connects to the database named by dbURL. URLs starting with
+// "sqlite:" name a SQLite file, e.g. sqlite:///home/me/gator.db or
+// sqlite:gator.db; anything else is passed to the Postgres driver.
+func Open(dbURL string) (*DB, error) {
+	if path, ok := strings.CutPrefix(dbURL, "sqlite:"); ok {
+		return openSQLite(path)
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DB{DB: db, Dialect: Postgres}, nil
+}
+
+// Queries returns the generated queries bound to db.
+func (db *DB) Queries() *database.Queries {
+	return db.bind(db.DB)
+}
+
+// InTx runs fn with queries bound to a new transaction. The transaction is
+// committed if fn succeeds and rolled back otherwise.
+func (db *DB) InTx(ctx context.Context, fn func(q *database.Queries) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(db.bind(tx))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *DB) bind(conn database.DBTX) *database.Queries {
+	if db.Dialect == SQLite {
+		return database.New(utcArgs{conn})
+	}
+
+	return database.New(conn)
+}
+
+// IsDuplicate reports whether err is a unique or primary key violation
+// reported by any backend.
+func IsDuplicate(err error) bool {
+	if errors.Is(err, ErrDuplicate) {
+		return true
+	}
+
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		return pgErr.Code.Name() == "unique_violation"
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code()
+		return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	}
+
+	return false
+}
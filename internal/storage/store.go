@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/google/uuid"
+)
+
+// ErrDuplicate is returned by Memory when a row would violate a unique
+// constraint. IsDuplicate recognises it like the SQL backends' errors.
+var ErrDuplicate = errors.New("duplicate key")
+
+// ErrNotSupported is returned for data a backend does not keep, such as
+// folders in Memory.
+var ErrNotSupported = errors.New("not supported by this storage backend")
+
+// Store is the data behind gator's core commands: users, the feeds they
+// follow and the posts collected from those feeds. The generated
+// *database.Queries implements it for the SQL backends and Memory keeps
+// everything in process.
+type Store interface {
+	Users
+	Feeds
+	Follows
+	Posts
+}
+
+// Users stores accounts.
+type Users interface {
+	CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error)
+	GetUser(ctx context.Context, name string) (database.User, error)
+	GetUsers(ctx context.Context) ([]database.User, error)
+	RenameUser(ctx context.Context, arg database.RenameUserParams) (int64, error)
+	DeleteUserByName(ctx context.Context, name string) (int64, error)
+	DeleteUser(ctx context.Context) error
+	SetUserRole(ctx context.Context, arg database.SetUserRoleParams) (int64, error)
+	CountUsersWithRole(ctx context.Context, role string) (int64, error)
+	GetUserStats(ctx context.Context, arg database.GetUserStatsParams) (database.GetUserStatsRow, error)
+}
+
+// Feeds stores feeds and the result of fetching them.
+type Feeds interface {
+	CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error)
+	GetFeed(ctx context.Context, url string) (database.Feed, error)
+	GetFeeds(ctx context.Context) ([]database.GetFeedsRow, error)
+	GetFeedByUrl(ctx context.Context, url string) (database.GetFeedByUrlRow, error)
+	GetFeedStats(ctx context.Context, feedID uuid.UUID) (database.GetFeedStatsRow, error)
+	RenameFeed(ctx context.Context, arg database.RenameFeedParams) (int64, error)
+	SetFeedUrl(ctx context.Context, arg database.SetFeedUrlParams) (int64, error)
+	DeleteFeedByUrl(ctx context.Context, url string) (int64, error)
+	GetNextFeedToFetch(ctx context.Context, nextFetchAt sql.NullTime) (database.Feed, error)
+	GetEarliestNextFetch(ctx context.Context) (sql.NullTime, error)
+	PostponeFeedFetch(ctx context.Context, arg database.PostponeFeedFetchParams) error
+	GetFeedPublishTimes(ctx context.Context, arg database.GetFeedPublishTimesParams) ([]sql.NullTime, error)
+	SetFeedFetchInterval(ctx context.Context, arg database.SetFeedFetchIntervalParams) (int64, error)
+	MarkFeedFetched(ctx context.Context, id uuid.UUID) (database.Feed, error)
+	RecordFeedFetch(ctx context.Context, arg database.RecordFeedFetchParams) error
+	SetFeedIsPodcast(ctx context.Context, arg database.SetFeedIsPodcastParams) error
+	SetFeedAutoDownload(ctx context.Context, arg database.SetFeedAutoDownloadParams) (int64, error)
+	DeleteFeeds(ctx context.Context) error
+}
+
+// Follows stores which users follow which feeds.
+type Follows interface {
+	CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error)
+	GetFeedFollowsForUser(ctx context.Context, name string) ([]database.GetFeedFollowsForUserRow, error)
+	DeleteFeedFollowByUrl(ctx context.Context, arg database.DeleteFeedFollowByUrlParams) error
+}
+
+// Posts stores the items collected from feeds and their categories.
+type Posts interface {
+	CreatePost(ctx context.Context, arg database.CreatePostParams) (database.Post, error)
+	CreatePostCategory(ctx context.Context, arg database.CreatePostCategoryParams) error
+	GetPostsForUser(ctx context.Context, arg database.GetPostsForUserParams) ([]database.Post, error)
+	GetTopCategoriesForUser(ctx context.Context, arg database.GetTopCategoriesForUserParams) ([]database.GetTopCategoriesForUserRow, error)
+}
+
+var _ Store = (*database.Queries)(nil)
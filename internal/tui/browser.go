@@ -0,0 +1,31 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// openBrowser opens url with $BROWSER if it is set, otherwise with the
+// platform's default handler. It does not wait for the browser to exit.
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch {
+	case os.Getenv("BROWSER") != "":
+		cmd = exec.Command(os.Getenv("BROWSER"), url)
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("open", url)
+	case runtime.GOOS == "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+
+	err := cmd.Start()
+	if err != nil {
+		return err
+	}
+
+	go cmd.Wait()
+	return nil
+}
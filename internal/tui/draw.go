@@ -0,0 +1,234 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// wideLayout is the narrowest terminal that shows all three panes side by
+// side. Narrower terminals show only the focused pane.
+const wideLayout = 90
+
+var (
+	plainStyle    = tcell.StyleDefault
+	titleStyle    = tcell.StyleDefault.Bold(true)
+	focusStyle    = tcell.StyleDefault.Reverse(true).Bold(true)
+	selectedStyle = tcell.StyleDefault.Reverse(true)
+	cursorStyle   = tcell.StyleDefault.Underline(true)
+	dimStyle      = tcell.StyleDefault.Dim(true)
+)
+
+type column struct {
+	pane  pane
+	x     int
+	width int
+}
+
+type line struct {
+	text  string
+	style tcell.Style
+}
+
+func (a *app) draw() {
+	a.screen.Clear()
+	a.screen.HideCursor()
+
+	width, height := a.screen.Size()
+	if width < 20 || height < 4 {
+		drawText(a.screen, 0, 0, width, plainStyle, "window too small")
+		a.screen.Show()
+		return
+	}
+
+	bodyHeight := height - 2
+	for i, c := range layout(width, a.focus) {
+		if i > 0 {
+			for y := 0; y < height-1; y++ {
+				a.screen.SetContent(c.x-1, y, tcell.RuneVLine, nil, dimStyle)
+			}
+		}
+
+		style := titleStyle
+		if c.pane == a.focus {
+			style = focusStyle
+		}
+		fill(a.screen, c.x, 0, c.width, style)
+		drawText(a.screen, c.x+1, 0, c.width-1, style, a.title(c.pane))
+
+		switch c.pane {
+		case feedPane:
+			a.drawList(c, bodyHeight, a.feedRows(), a.nodeIndex, &a.nodeOffset)
+		case postPane:
+			a.drawList(c, bodyHeight, a.postRows(), a.postIndex, &a.postOffset)
+		case readerPane:
+			a.drawReader(c, bodyHeight)
+		}
+	}
+
+	a.drawStatus(width, height-1)
+	a.screen.Show()
+}
+
+func layout(width int, focus pane) []column {
+	if width < wideLayout {
+		return []column{{pane: focus, x: 0, width: width}}
+	}
+
+	feedWidth := width / 5
+	postWidth := (width - feedWidth) * 2 / 5
+	return []column{
+		{pane: feedPane, x: 0, width: feedWidth},
+		{pane: postPane, x: feedWidth + 1, width: postWidth},
+		{pane: readerPane, x: feedWidth + postWidth + 2, width: width - feedWidth - postWidth - 2},
+	}
+}
+
+func (a *app) title(p pane) string {
+	switch p {
+	case feedPane:
+		return "Feeds"
+	case postPane:
+		label := ""
+		if a.nodeIndex < len(a.nodes) {
+			label = a.nodes[a.nodeIndex].label
+		}
+		return fmt.Sprintf("%s (%d)", label, len(a.posts))
+	default:
+		return "Reader"
+	}
+}
+
+func (a *app) feedRows() []string {
+	var rows []string
+	for _, n := range a.nodes {
+		if n.kind == feedNode && n.folder != "" {
+			rows = append(rows, "  "+n.label)
+			continue
+		}
+		rows = append(rows, n.label)
+	}
+
+	return rows
+}
+
+func (a *app) postRows() []string {
+	showFeed := a.nodeIndex < len(a.nodes) && a.nodes[a.nodeIndex].kind != feedNode
+
+	var rows []string
+	for _, post := range a.posts {
+		date := "      "
+		if post.PublishedAt.Valid {
+			date = post.PublishedAt.Time.Local().Format("Jan 02")
+		}
+
+		row := fmt.Sprintf("%s  %s", date, post.Title)
+		if showFeed {
+			row += " · " + a.feeds[post.FeedID].label
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// drawList draws rows into column c below its title, scrolling offset so the
+// selected row stays visible.
+func (a *app) drawList(c column, height int, rows []string, selected int, offset *int) {
+	if selected < *offset {
+		*offset = selected
+	}
+	if selected >= *offset+height {
+		*offset = selected - height + 1
+	}
+
+	for i := 0; i < height && *offset+i < len(rows); i++ {
+		index := *offset + i
+		style := plainStyle
+		if index == selected {
+			style = cursorStyle
+			if c.pane == a.focus {
+				style = selectedStyle
+			}
+			fill(a.screen, c.x, i+1, c.width, style)
+		}
+		drawText(a.screen, c.x+1, i+1, c.width-2, style, rows[index])
+	}
+}
+
+func (a *app) drawReader(c column, height int) {
+	lines := a.readerLines(c.width - 2)
+	a.readerOffset = clamp(a.readerOffset, 0, len(lines)-height)
+
+	for i := 0; i < height && a.readerOffset+i < len(lines); i++ {
+		l := lines[a.readerOffset+i]
+		drawText(a.screen, c.x+1, i+1, c.width-2, l.style, l.text)
+	}
+}
+
+func (a *app) readerLines(width int) []line {
+	post, ok := a.selectedPost()
+	if !ok {
+		return []line{{text: "No posts yet. Run gator agg to collect some.", style: dimStyle}}
+	}
+
+	var lines []line
+	for _, text := range wrap(post.Title, width) {
+		lines = append(lines, line{text: text, style: titleStyle})
+	}
+
+	meta := a.feeds[post.FeedID].label
+	if post.Author.Valid && post.Author.String != "" {
+		meta += " · " + post.Author.String
+	}
+	if post.PublishedAt.Valid {
+		meta += " · " + post.PublishedAt.Time.Local().Format("Mon, 02 Jan 2006 15:04")
+	}
+	for _, text := range wrap(meta, width) {
+		lines = append(lines, line{text: text, style: dimStyle})
+	}
+	for _, text := range wrap(post.Url, width) {
+		lines = append(lines, line{text: text, style: cursorStyle})
+	}
+	lines = append(lines, line{})
+
+	for _, text := range wrap(plainText(post.Description.String), width) {
+		lines = append(lines, line{text: text, style: plainStyle})
+	}
+
+	return lines
+}
+
+func (a *app) drawStatus(width, y int) {
+	if a.prompt != nil {
+		text := a.prompt.label + string(a.prompt.input)
+		drawText(a.screen, 0, y, width, plainStyle, text)
+		a.screen.ShowCursor(min(runewidth.StringWidth(text), width-1), y)
+		return
+	}
+
+	user := " " + a.user.Name + " "
+	userWidth := runewidth.StringWidth(user)
+	drawText(a.screen, 0, y, width-userWidth-1, dimStyle, a.status)
+	drawText(a.screen, width-userWidth, y, userWidth, selectedStyle, user)
+}
+
+// drawText draws text at x, y, cutting it off after width cells.
+func drawText(screen tcell.Screen, x, y, width int, style tcell.Style, text string) {
+	column := 0
+	for _, r := range text {
+		cells := runewidth.RuneWidth(r)
+		if column+cells > width {
+			return
+		}
+		screen.SetContent(x+column, y, r, nil, style)
+		column += cells
+	}
+}
+
+func fill(screen tcell.Screen, x, y, width int, style tcell.Style) {
+	for i := 0; i < width; i++ {
+		screen.SetContent(x+i, y, ' ', nil, style)
+	}
+}
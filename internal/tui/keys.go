@@ -0,0 +1,252 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/gdamore/tcell/v2"
+	"github.com/google/uuid"
+)
+
+const helpText = "tab/←→ pane  ↑↓ move  enter open  o browser  a follow  d unfollow  r refresh  q quit"
+
+// edge is a cursor movement large enough to reach either end of any pane.
+const edge = 1 << 30
+
+func (a *app) handleKey(event *tcell.EventKey) {
+	if a.prompt != nil {
+		a.handlePromptKey(event)
+		return
+	}
+
+	a.status = helpText
+
+	switch event.Key() {
+	case tcell.KeyCtrlC:
+		a.quit = true
+	case tcell.KeyTab, tcell.KeyRight:
+		a.changeFocus(1)
+	case tcell.KeyBacktab, tcell.KeyLeft:
+		a.changeFocus(-1)
+	case tcell.KeyEscape:
+		a.changeFocus(-1)
+	case tcell.KeyUp:
+		a.move(-1)
+	case tcell.KeyDown:
+		a.move(1)
+	case tcell.KeyPgUp:
+		a.move(-a.pageSize())
+	case tcell.KeyPgDn:
+		a.move(a.pageSize())
+	case tcell.KeyHome:
+		a.move(-edge)
+	case tcell.KeyEnd:
+		a.move(edge)
+	case tcell.KeyEnter:
+		a.open()
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'q':
+			a.quit = true
+		case 'h':
+			a.changeFocus(-1)
+		case 'l':
+			a.changeFocus(1)
+		case 'k':
+			a.move(-1)
+		case 'j':
+			a.move(1)
+		case ' ':
+			a.move(a.pageSize())
+		case 'g':
+			a.move(-edge)
+		case 'G':
+			a.move(edge)
+		case 'o':
+			a.openInBrowser()
+		case 'a':
+			a.askFollow()
+		case 'd':
+			a.askUnfollow()
+		case 'r':
+			a.reload()
+			a.status = fmt.Sprintf("refreshed at %s", time.Now().Format(time.Kitchen))
+		}
+	}
+}
+
+func (a *app) handlePromptKey(event *tcell.EventKey) {
+	switch event.Key() {
+	case tcell.KeyEnter:
+		p := a.prompt
+		a.prompt = nil
+		p.submit(strings.TrimSpace(string(p.input)))
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		a.prompt = nil
+		a.status = "cancelled"
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(a.prompt.input) > 0 {
+			a.prompt.input = a.prompt.input[:len(a.prompt.input)-1]
+		}
+	case tcell.KeyRune:
+		a.prompt.input = append(a.prompt.input, event.Rune())
+	}
+}
+
+func (a *app) changeFocus(delta int) {
+	focus := int(a.focus) + delta
+	if focus < int(feedPane) || focus > int(readerPane) {
+		return
+	}
+
+	a.focus = pane(focus)
+}
+
+// move shifts the cursor of the focused pane by delta rows.
+func (a *app) move(delta int) {
+	switch a.focus {
+	case feedPane:
+		index := clamp(a.nodeIndex+delta, 0, len(a.nodes)-1)
+		if index != a.nodeIndex {
+			a.nodeIndex = index
+			a.loadPosts()
+		}
+	case postPane:
+		index := clamp(a.postIndex+delta, 0, len(a.posts)-1)
+		if index != a.postIndex {
+			a.postIndex = index
+			a.readerOffset = 0
+		}
+	case readerPane:
+		// The upper bound depends on the wrapped text and is applied in draw.
+		a.readerOffset = max(a.readerOffset+delta, 0)
+	}
+}
+
+func (a *app) open() {
+	switch a.focus {
+	case feedPane, postPane:
+		a.changeFocus(1)
+	case readerPane:
+		a.openInBrowser()
+	}
+}
+
+func (a *app) openInBrowser() {
+	post, ok := a.selectedPost()
+	if !ok || a.focus == feedPane {
+		return
+	}
+
+	err := openBrowser(post.Url)
+	if err != nil {
+		a.status = fmt.Sprintf("couldn't open browser: %v", err)
+		return
+	}
+
+	a.status = fmt.Sprintf("opened %s", post.Url)
+}
+
+func (a *app) askFollow() {
+	a.prompt = &prompt{
+		label:  "follow feed url: ",
+		submit: a.follow,
+	}
+}
+
+func (a *app) follow(url string) {
+	if url == "" {
+		return
+	}
+
+	feed, err := a.db.GetFeedByUrl(a.ctx, url)
+	if err != nil {
+		a.status = fmt.Sprintf("no feed with url %s, add it with gator addfeed first", url)
+		return
+	}
+
+	currentTime := time.Now()
+	args := database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+		UserID:    a.user.ID,
+		FeedID:    feed.ID,
+	}
+	_, err = a.db.CreateFeedFollow(a.ctx, args)
+	if err != nil {
+		a.status = fmt.Sprintf("couldn't follow %s: %v", url, err)
+		return
+	}
+
+	a.reload()
+	a.status = fmt.Sprintf("following %s", feed.FeedName)
+}
+
+// askUnfollow offers to unfollow the selected feed, or the feed of the
+// selected post when the post list or reader has focus.
+func (a *app) askUnfollow() {
+	var feed node
+	switch a.focus {
+	case feedPane:
+		if a.nodeIndex >= len(a.nodes) {
+			return
+		}
+		feed = a.nodes[a.nodeIndex]
+	default:
+		post, ok := a.selectedPost()
+		if !ok {
+			return
+		}
+		feed = a.feeds[post.FeedID]
+	}
+
+	if feed.kind != feedNode {
+		a.status = "select a feed to unfollow"
+		return
+	}
+
+	a.prompt = &prompt{
+		label: fmt.Sprintf("unfollow %s? (y/n) ", feed.label),
+		submit: func(answer string) {
+			if !strings.HasPrefix(strings.ToLower(answer), "y") {
+				a.status = "cancelled"
+				return
+			}
+			a.unfollow(feed)
+		},
+	}
+}
+
+func (a *app) unfollow(feed node) {
+	deleteFeedFollowByUrlParams := database.DeleteFeedFollowByUrlParams{
+		UserID: a.user.ID,
+		Url:    feed.feedURL,
+	}
+	err := a.db.DeleteFeedFollowByUrl(a.ctx, deleteFeedFollowByUrlParams)
+	if err != nil {
+		a.status = fmt.Sprintf("couldn't unfollow %s: %v", feed.label, err)
+		return
+	}
+
+	a.reload()
+	a.status = fmt.Sprintf("unfollowed %s", feed.label)
+}
+
+func (a *app) pageSize() int {
+	_, height := a.screen.Size()
+	return max(height-3, 1)
+}
+
+func clamp(value, low, high int) int {
+	if value > high {
+		value = high
+	}
+	if value < low {
+		value = low
+	}
+
+	return value
+}
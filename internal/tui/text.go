@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+var (
+	lineBreak  = regexp.MustCompile(`(?i)<\s*br\s*/?\s*>`)
+	blockEnd   = regexp.MustCompile(`(?i)<\s*/\s*(p|div|h[1-6]|blockquote|pre|ul|ol|table|tr)\s*>`)
+	listItem   = regexp.MustCompile(`(?i)<\s*li\b[^>]*>`)
+	anyTag     = regexp.MustCompile(`(?s)<[^>]*>`)
+	spaceRun   = regexp.MustCompile(`[ \t\r\f\v]+`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// plainText turns the HTML found in item descriptions into paragraphs of
+// plain text suitable for the reader pane.
+func plainText(value string) string {
+	value = lineBreak.ReplaceAllString(value, "\n")
+	value = blockEnd.ReplaceAllString(value, "\n\n")
+	value = listItem.ReplaceAllString(value, "\n• ")
+	value = anyTag.ReplaceAllString(value, "")
+	value = html.UnescapeString(value)
+
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spaceRun.ReplaceAllString(line, " "))
+	}
+
+	value = strings.Join(lines, "\n")
+	value = blankLines.ReplaceAllString(value, "\n\n")
+	return strings.TrimSpace(value)
+}
+
+// wrap breaks text into lines no wider than width cells. Existing line breaks
+// are kept and words longer than a whole line are split.
+func wrap(text string, width int) []string {
+	if width < 1 {
+		return nil
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for runewidth.StringWidth(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				head := runewidth.Truncate(word, width, "")
+				lines = append(lines, head)
+				word = word[len(head):]
+			}
+
+			switch {
+			case line == "":
+				line = word
+			case runewidth.StringWidth(line)+1+runewidth.StringWidth(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -0,0 +1,242 @@
+// Package tui implements gator's full-screen terminal reader.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ctiller15/gator/internal/database"
+	"github.com/gdamore/tcell/v2"
+	"github.com/google/uuid"
+)
+
+type pane int
+
+const (
+	feedPane pane = iota
+	postPane
+	readerPane
+)
+
+type nodeKind int
+
+const (
+	allNode nodeKind = iota
+	folderNode
+	feedNode
+)
+
+// node is a row of the feed pane: all followed posts, a folder or a feed.
+type node struct {
+	kind    nodeKind
+	label   string
+	folder  string
+	feedURL string
+}
+
+// key identifies a node across reloads so the selection survives a refresh.
+func (n node) key() string {
+	return fmt.Sprintf("%d/%s/%s", n.kind, n.folder, n.feedURL)
+}
+
+// prompt is a single-line question shown in the status bar.
+type prompt struct {
+	label  string
+	input  []rune
+	submit func(answer string)
+}
+
+// Options configures the reader.
+type Options struct {
+	// Limit is the maximum number of posts listed for the selected node.
+	Limit int
+	// Refresh is how often feeds and posts are reloaded from the database.
+	Refresh time.Duration
+}
+
+type app struct {
+	ctx     context.Context
+	db      *database.Queries
+	user    database.User
+	options Options
+	screen  tcell.Screen
+
+	nodes []node
+	feeds map[uuid.UUID]node
+	posts []database.Post
+
+	focus        pane
+	nodeIndex    int
+	postIndex    int
+	nodeOffset   int
+	postOffset   int
+	readerOffset int
+
+	status string
+	prompt *prompt
+	quit   bool
+}
+
+// Run shows the reader for user until they quit.
+func Run(ctx context.Context, db *database.Queries, user database.User, options Options) error {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return err
+	}
+
+	err = screen.Init()
+	if err != nil {
+		return err
+	}
+	defer screen.Fini()
+
+	a := &app{
+		ctx:     ctx,
+		db:      db,
+		user:    user,
+		options: options,
+		screen:  screen,
+		status:  helpText,
+	}
+	a.reload()
+
+	events := make(chan tcell.Event)
+	stop := make(chan struct{})
+	defer close(stop)
+	go screen.ChannelEvents(events, stop)
+
+	ticker := time.NewTicker(options.Refresh)
+	defer ticker.Stop()
+
+	for !a.quit {
+		a.draw()
+
+		select {
+		case event := <-events:
+			switch event := event.(type) {
+			case *tcell.EventResize:
+				screen.Sync()
+			case *tcell.EventKey:
+				a.handleKey(event)
+			}
+		case <-ticker.C:
+			a.reload()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// reload re-reads the followed feeds and the posts of the selected node,
+// keeping the current selection where it still exists.
+func (a *app) reload() {
+	selectedNode := ""
+	if a.nodeIndex < len(a.nodes) {
+		selectedNode = a.nodes[a.nodeIndex].key()
+	}
+
+	err := a.loadNodes()
+	if err != nil {
+		a.status = fmt.Sprintf("couldn't load feeds: %v", err)
+		return
+	}
+
+	a.nodeIndex = 0
+	for i, n := range a.nodes {
+		if n.key() == selectedNode {
+			a.nodeIndex = i
+		}
+	}
+
+	a.loadPosts()
+}
+
+func (a *app) loadNodes() error {
+	follows, err := a.db.GetFeedFollowsForUser(a.ctx, a.user.Name)
+	if err != nil {
+		return err
+	}
+
+	folders, err := a.db.GetFoldersForUser(a.ctx, a.user.ID)
+	if err != nil {
+		return err
+	}
+
+	nodes := []node{{kind: allNode, label: "All posts"}}
+	feeds := make(map[uuid.UUID]node)
+	folderFeeds := make(map[string][]node)
+	for _, follow := range follows {
+		feed := node{kind: feedNode, label: follow.FeedName, feedURL: follow.FeedUrl}
+		if follow.FolderName.Valid {
+			feed.folder = follow.FolderName.String
+		}
+		feeds[follow.FeedID] = feed
+
+		if feed.folder == "" {
+			nodes = append(nodes, feed)
+			continue
+		}
+		folderFeeds[feed.folder] = append(folderFeeds[feed.folder], feed)
+	}
+
+	for _, folder := range folders {
+		nodes = append(nodes, node{kind: folderNode, label: folder.Name + "/", folder: folder.Name})
+		nodes = append(nodes, folderFeeds[folder.Name]...)
+	}
+
+	a.nodes = nodes
+	a.feeds = feeds
+	return nil
+}
+
+// loadPosts lists the posts of the selected node, keeping the selected post
+// if it is still listed.
+func (a *app) loadPosts() {
+	var selectedPost uuid.UUID
+	if a.postIndex < len(a.posts) {
+		selectedPost = a.posts[a.postIndex].ID
+	}
+
+	params := database.GetPostsForUserParams{
+		ID:         a.user.ID,
+		LimitCount: int32(a.options.Limit),
+	}
+	if a.nodeIndex < len(a.nodes) {
+		selected := a.nodes[a.nodeIndex]
+		switch selected.kind {
+		case folderNode:
+			params.Folder = selected.folder
+		case feedNode:
+			params.FeedUrl = selected.feedURL
+		}
+	}
+
+	posts, err := a.db.GetPostsForUser(a.ctx, params)
+	if err != nil {
+		a.status = fmt.Sprintf("couldn't load posts: %v", err)
+		return
+	}
+
+	a.posts = posts
+	for i, post := range posts {
+		if post.ID == selectedPost {
+			a.postIndex = i
+			return
+		}
+	}
+
+	a.postIndex = 0
+	a.postOffset = 0
+	a.readerOffset = 0
+}
+
+func (a *app) selectedPost() (database.Post, bool) {
+	if a.postIndex >= len(a.posts) {
+		return database.Post{}, false
+	}
+
+	return a.posts[a.postIndex], true
+}
@@ -1,40 +1,46 @@
 package main
 
 import (
-	"database/sql"
+	"flag"
 	"log"
 	"os"
 
 	"github.com/ctiller15/gator/internal/commands"
 	"github.com/ctiller15/gator/internal/config"
-	"github.com/ctiller15/gator/internal/database"
-	_ "github.com/lib/pq"
+	"github.com/ctiller15/gator/internal/storage"
 )
 
 func main() {
-	configStruct, err := config.Read()
+	fs := flag.NewFlagSet("gator", flag.ExitOnError)
+	configPath := fs.String("config", "", "path of the config file")
+	profile := fs.String("profile", "", "config profile to use")
+	fs.Parse(os.Args[1:])
+
+	configOptions := config.Options{
+		Path:    *configPath,
+		Profile: *profile,
+	}
+	configStruct, err := config.Read(configOptions)
 	if err != nil {
 		log.Fatalf("error occurred: %v", err)
 	}
 
-	db, err := sql.Open("postgres", configStruct.DB_URL)
+	db, err := storage.Open(configStruct.DB_URL)
 	if err != nil {
 		log.Fatalf("error occurred: %v", err)
 	}
 
-	dbQueries := database.New(db)
-
-	newState := commands.NewState(&configStruct, dbQueries)
+	newState := commands.NewState(&configStruct, db.Queries(), db)
 
 	newCommands := commands.NewCommands()
 
-	args := os.Args
-	if len(args) < 2 {
-		log.Fatal("requires at least 2 arguments")
+	args := fs.Args()
+	if len(args) < 1 {
+		log.Fatal("usage: gator [--config <file>] [--profile <name>] <command> [args...]")
 	}
 
-	commandName := args[1]
-	commandArgs := args[2:]
+	commandName := args[0]
+	commandArgs := args[1:]
 
 	command := commands.NewCommand(commandName, commandArgs)
 	err = newCommands.Run(newState, command)
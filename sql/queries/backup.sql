@@ -0,0 +1,275 @@
+-- name: CountRows :one
+SELECT
+    (SELECT COUNT(*) FROM users) AS users,
+    (SELECT COUNT(*) FROM feeds) AS feeds,
+    (SELECT COUNT(*) FROM feed_follows) AS feed_follows,
+    (SELECT COUNT(*) FROM posts) AS posts;
+
+-- name: DumpUsers :many
+SELECT *
+FROM users
+ORDER BY created_at, id;
+
+-- name: DumpFeeds :many
+SELECT *
+FROM feeds
+ORDER BY created_at, id;
+
+-- name: DumpFolders :many
+SELECT *
+FROM folders
+ORDER BY created_at, id;
+
+-- name: DumpFeedFollows :many
+SELECT *
+FROM feed_follows
+ORDER BY created_at, id;
+
+-- name: DumpFilterRules :many
+SELECT *
+FROM filter_rules
+ORDER BY created_at, id;
+
+-- name: DumpWebhooks :many
+SELECT *
+FROM webhooks
+ORDER BY created_at, id;
+
+-- name: DumpPosts :many
+SELECT *
+FROM posts
+ORDER BY created_at, id;
+
+-- name: DumpPostCategories :many
+SELECT *
+FROM post_categories
+ORDER BY post_id, name;
+
+-- name: DumpEnclosures :many
+SELECT *
+FROM enclosures
+ORDER BY created_at, id;
+
+-- name: DumpWebhookDeliveries :many
+SELECT *
+FROM webhook_deliveries
+ORDER BY created_at, id;
+
+-- name: RestoreUser :exec
+INSERT INTO users (id, created_at, updated_at, name, email, last_digest_at, role)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+ON CONFLICT (id) DO UPDATE
+SET created_at = excluded.created_at,
+updated_at = excluded.updated_at,
+name = excluded.name,
+email = excluded.email,
+last_digest_at = excluded.last_digest_at,
+role = excluded.role;
+
+-- name: RestoreFeed :exec
+INSERT INTO feeds (id, created_at, updated_at, name, url, last_fetched_at, is_podcast, auto_download, last_fetch_status, last_fetch_error, last_fetch_new_posts, added_by, next_fetch_at, fetch_interval_seconds)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14
+)
+ON CONFLICT (id) DO UPDATE
+SET created_at = excluded.created_at,
+updated_at = excluded.updated_at,
+name = excluded.name,
+url = excluded.url,
+last_fetched_at = excluded.last_fetched_at,
+is_podcast = excluded.is_podcast,
+auto_download = excluded.auto_download,
+last_fetch_status = excluded.last_fetch_status,
+last_fetch_error = excluded.last_fetch_error,
+last_fetch_new_posts = excluded.last_fetch_new_posts,
+added_by = excluded.added_by,
+next_fetch_at = excluded.next_fetch_at,
+fetch_interval_seconds = excluded.fetch_interval_seconds;
+
+-- name: RestoreFolder :exec
+INSERT INTO folders (id, created_at, updated_at, user_id, name)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (id) DO UPDATE
+SET created_at = excluded.created_at,
+updated_at = excluded.updated_at,
+user_id = excluded.user_id,
+name = excluded.name;
+
+-- name: RestoreFeedFollow :exec
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id, folder_id)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+ON CONFLICT (id) DO UPDATE
+SET created_at = excluded.created_at,
+updated_at = excluded.updated_at,
+user_id = excluded.user_id,
+feed_id = excluded.feed_id,
+folder_id = excluded.folder_id;
+
+-- name: RestoreFilterRule :exec
+INSERT INTO filter_rules (id, created_at, updated_at, user_id, action, field, pattern, is_regex, feed_id)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+)
+ON CONFLICT (id) DO UPDATE
+SET created_at = excluded.created_at,
+updated_at = excluded.updated_at,
+user_id = excluded.user_id,
+action = excluded.action,
+field = excluded.field,
+pattern = excluded.pattern,
+is_regex = excluded.is_regex,
+feed_id = excluded.feed_id;
+
+-- name: RestoreWebhook :exec
+INSERT INTO webhooks (id, created_at, updated_at, user_id, url, secret, format, batch, feed_id, keyword)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10
+)
+ON CONFLICT (id) DO UPDATE
+SET created_at = excluded.created_at,
+updated_at = excluded.updated_at,
+user_id = excluded.user_id,
+url = excluded.url,
+secret = excluded.secret,
+format = excluded.format,
+batch = excluded.batch,
+feed_id = excluded.feed_id,
+keyword = excluded.keyword;
+
+-- name: RestorePost :exec
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, author)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+)
+ON CONFLICT (id) DO UPDATE
+SET created_at = excluded.created_at,
+updated_at = excluded.updated_at,
+title = excluded.title,
+url = excluded.url,
+description = excluded.description,
+published_at = excluded.published_at,
+feed_id = excluded.feed_id,
+author = excluded.author;
+
+-- name: RestorePostCategory :exec
+INSERT INTO post_categories (post_id, name)
+VALUES (
+    $1,
+    $2
+)
+ON CONFLICT DO NOTHING;
+
+-- name: RestoreEnclosure :exec
+INSERT INTO enclosures (id, created_at, updated_at, post_id, url, mime_type, length, duration_seconds, image_url, local_path, downloaded_bytes, sha256, downloaded_at, played_at)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14
+)
+ON CONFLICT (id) DO UPDATE
+SET created_at = excluded.created_at,
+updated_at = excluded.updated_at,
+post_id = excluded.post_id,
+url = excluded.url,
+mime_type = excluded.mime_type,
+length = excluded.length,
+duration_seconds = excluded.duration_seconds,
+image_url = excluded.image_url,
+local_path = excluded.local_path,
+downloaded_bytes = excluded.downloaded_bytes,
+sha256 = excluded.sha256,
+downloaded_at = excluded.downloaded_at,
+played_at = excluded.played_at;
+
+-- name: RestoreWebhookDelivery :exec
+INSERT INTO webhook_deliveries (id, created_at, webhook_id, post_id, post_count, attempts, status_code, error, delivered)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+)
+ON CONFLICT (id) DO UPDATE
+SET created_at = excluded.created_at,
+webhook_id = excluded.webhook_id,
+post_id = excluded.post_id,
+post_count = excluded.post_count,
+attempts = excluded.attempts,
+status_code = excluded.status_code,
+error = excluded.error,
+delivered = excluded.delivered;
\ No newline at end of file
@@ -0,0 +1,91 @@
+-- name: GetDigestPostsForUser :many
+SELECT posts.*, feeds.name AS feed_name, feeds.url AS feed_url
+FROM posts
+INNER JOIN feeds
+ON posts.feed_id = feeds.id
+INNER JOIN feed_follows
+ON feeds.id = feed_follows.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+AND posts.created_at > sqlc.arg(since)
+AND posts.created_at <= sqlc.arg(until)
+AND NOT EXISTS (
+    SELECT 1
+    FROM filter_rules
+    INNER JOIN post_filter_targets
+    ON post_filter_targets.field = filter_rules.field
+    WHERE filter_rules.user_id = feed_follows.user_id
+    AND filter_rules.action = 'exclude'
+    AND (filter_rules.feed_id IS NULL OR filter_rules.feed_id = posts.feed_id)
+    AND post_filter_targets.post_id = posts.id
+    AND CASE WHEN filter_rules.is_regex
+        THEN regexp_like(post_filter_targets.value, filter_rules.pattern, 'i')
+        ELSE strpos(lower(post_filter_targets.value), lower(filter_rules.pattern)) > 0
+    END
+)
+AND (NOT EXISTS (
+    SELECT 1
+    FROM filter_rules
+    WHERE filter_rules.user_id = feed_follows.user_id
+    AND filter_rules.action = 'include'
+    AND (filter_rules.feed_id IS NULL OR filter_rules.feed_id = posts.feed_id)
+) OR EXISTS (
+    SELECT 1
+    FROM filter_rules
+    INNER JOIN post_filter_targets
+    ON post_filter_targets.field = filter_rules.field
+    WHERE filter_rules.user_id = feed_follows.user_id
+    AND filter_rules.action = 'include'
+    AND (filter_rules.feed_id IS NULL OR filter_rules.feed_id = posts.feed_id)
+    AND post_filter_targets.post_id = posts.id
+    AND CASE WHEN filter_rules.is_regex
+        THEN regexp_like(post_filter_targets.value, filter_rules.pattern, 'i')
+        ELSE strpos(lower(post_filter_targets.value), lower(filter_rules.pattern)) > 0
+    END
+))
+ORDER BY feeds.name ASC, posts.published_at DESC NULLS LAST;
+
+-- name: GetPostsPublishedForUser :many
+SELECT posts.*, feeds.name AS feed_name, feeds.url AS feed_url
+FROM posts
+INNER JOIN feeds
+ON posts.feed_id = feeds.id
+INNER JOIN feed_follows
+ON feeds.id = feed_follows.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+AND posts.published_at >= sqlc.arg(published_from)
+AND posts.published_at < sqlc.arg(published_to)
+AND NOT EXISTS (
+    SELECT 1
+    FROM filter_rules
+    INNER JOIN post_filter_targets
+    ON post_filter_targets.field = filter_rules.field
+    WHERE filter_rules.user_id = feed_follows.user_id
+    AND filter_rules.action = 'exclude'
+    AND (filter_rules.feed_id IS NULL OR filter_rules.feed_id = posts.feed_id)
+    AND post_filter_targets.post_id = posts.id
+    AND CASE WHEN filter_rules.is_regex
+        THEN regexp_like(post_filter_targets.value, filter_rules.pattern, 'i')
+        ELSE strpos(lower(post_filter_targets.value), lower(filter_rules.pattern)) > 0
+    END
+)
+AND (NOT EXISTS (
+    SELECT 1
+    FROM filter_rules
+    WHERE filter_rules.user_id = feed_follows.user_id
+    AND filter_rules.action = 'include'
+    AND (filter_rules.feed_id IS NULL OR filter_rules.feed_id = posts.feed_id)
+) OR EXISTS (
+    SELECT 1
+    FROM filter_rules
+    INNER JOIN post_filter_targets
+    ON post_filter_targets.field = filter_rules.field
+    WHERE filter_rules.user_id = feed_follows.user_id
+    AND filter_rules.action = 'include'
+    AND (filter_rules.feed_id IS NULL OR filter_rules.feed_id = posts.feed_id)
+    AND post_filter_targets.post_id = posts.id
+    AND CASE WHEN filter_rules.is_regex
+        THEN regexp_like(post_filter_targets.value, filter_rules.pattern, 'i')
+        ELSE strpos(lower(post_filter_targets.value), lower(filter_rules.pattern)) > 0
+    END
+))
+ORDER BY feeds.name ASC, posts.published_at DESC;
\ No newline at end of file
@@ -0,0 +1,89 @@
+-- name: CreateEnclosure :one
+INSERT INTO enclosures (id, created_at, updated_at, post_id, url, mime_type, length, duration_seconds, image_url)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+)
+RETURNING *;
+
+-- name: GetEpisodesForUser :many
+SELECT
+    enclosures.*,
+    posts.title AS post_title,
+    posts.published_at,
+    feeds.name AS feed_name
+FROM enclosures
+INNER JOIN posts
+ON enclosures.post_id = posts.id
+INNER JOIN feeds
+ON posts.feed_id = feeds.id
+INNER JOIN feed_follows
+ON feeds.id = feed_follows.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+AND (enclosures.mime_type LIKE 'audio/%' OR enclosures.mime_type LIKE 'video/%')
+AND (feeds.url = sqlc.arg(feed_url) OR sqlc.arg(feed_url) = '')
+ORDER BY posts.published_at DESC NULLS LAST
+LIMIT sqlc.arg(limit_count);
+
+-- name: GetPendingDownloadsForUser :many
+SELECT
+    enclosures.*,
+    posts.title AS post_title,
+    posts.published_at,
+    feeds.name AS feed_name
+FROM enclosures
+INNER JOIN posts
+ON enclosures.post_id = posts.id
+INNER JOIN feeds
+ON posts.feed_id = feeds.id
+INNER JOIN feed_follows
+ON feeds.id = feed_follows.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+AND feeds.is_podcast
+AND enclosures.local_path IS NULL
+AND (enclosures.mime_type LIKE 'audio/%' OR enclosures.mime_type LIKE 'video/%')
+AND (feeds.url = sqlc.arg(feed_url) OR sqlc.arg(feed_url) = '')
+ORDER BY posts.published_at DESC NULLS LAST
+LIMIT sqlc.arg(limit_count);
+
+-- name: MarkEnclosureDownloaded :exec
+UPDATE enclosures
+SET local_path = $2,
+downloaded_bytes = $3,
+sha256 = $4,
+downloaded_at = current_timestamp,
+updated_at = current_timestamp
+WHERE enclosures.id = $1;
+
+-- name: ClearEnclosureDownload :exec
+UPDATE enclosures
+SET local_path = NULL,
+downloaded_bytes = NULL,
+sha256 = NULL,
+downloaded_at = NULL,
+updated_at = current_timestamp
+WHERE enclosures.id = $1;
+
+-- name: GetDownloadedEnclosures :many
+SELECT *
+FROM enclosures
+WHERE local_path IS NOT NULL
+ORDER BY played_at IS NULL, COALESCE(played_at, downloaded_at) ASC;
+
+-- name: MarkEnclosurePlayed :execrows
+UPDATE enclosures
+SET played_at = current_timestamp,
+updated_at = current_timestamp
+WHERE enclosures.url = $1
+OR enclosures.post_id IN (
+    SELECT id
+    FROM posts
+    WHERE posts.url = $1
+);
\ No newline at end of file
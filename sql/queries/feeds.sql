@@ -1,22 +1,30 @@
 -- name: CreateFeed :one
-INSERT INTO feeds (id, created_at, updated_at, name, url)
+INSERT INTO feeds (id, created_at, updated_at, name, url, added_by)
 VALUES (
     $1,
     $2,
     $3,
     $4,
-    $5
+    $5,
+    $6
 )
 RETURNING *;
 
 -- name: GetFeeds :many
-SELECT feeds.name AS feed_name, feeds.url, users.name AS user_name
+SELECT feeds.name AS feed_name,
+    feeds.url,
+    feeds.is_podcast,
+    feeds.last_fetched_at,
+    feeds.last_fetch_status,
+    users.name AS added_by_name,
+    (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.feed_id = feeds.id) AS followers
 FROM feeds
-INNER JOIN users
-ON feeds.user_id = users.id;
+LEFT JOIN users
+ON feeds.added_by = users.id
+ORDER BY feeds.created_at, feeds.id;
 
 -- name: CreateFeedFollow :one
-WITH inserted_feed_follow AS (INSERT INTO feed_follows (
+INSERT INTO feed_follows (
     id,
     created_at,
     updated_at,
@@ -30,18 +38,9 @@ VALUES (
     $4,
     $5
 )
-RETURNING  *
-)
-
-SELECT 
-    inserted_feed_follow.*,
-    feeds.name AS feed_name,
-    users.name AS user_name
-    FROM inserted_feed_follow
-    INNER JOIN users
-    ON users.id = inserted_feed_follow.user_id
-    INNER JOIN feeds
-    ON feeds.id = inserted_feed_follow.feed_id;
+RETURNING *,
+    (SELECT feeds.name FROM feeds WHERE feeds.id = feed_follows.feed_id) AS feed_name,
+    (SELECT users.name FROM users WHERE users.id = feed_follows.user_id) AS user_name;
 
 -- name: GetFeedByUrl :one
 SELECT id, feeds.name AS feed_name
@@ -49,18 +48,67 @@ FROM feeds
 WHERE url = $1
 LIMIT 1;
 
+-- name: GetFeed :one
+SELECT *
+FROM feeds
+WHERE url = $1
+LIMIT 1;
+
+-- name: GetFeedStats :one
+SELECT
+    (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.feed_id = $1) AS followers,
+    (SELECT COUNT(*) FROM posts WHERE posts.feed_id = $1) AS posts;
+
+-- name: RenameFeed :execrows
+UPDATE feeds
+SET name = $2,
+updated_at = current_timestamp
+WHERE url = $1;
+
+-- name: SetFeedUrl :execrows
+UPDATE feeds
+SET url = sqlc.arg(new_url),
+updated_at = current_timestamp
+WHERE url = sqlc.arg(old_url);
+
+-- name: DeleteFeedByUrl :execrows
+DELETE FROM feeds
+WHERE url = $1;
+
+-- name: MergeFeedFollows :exec
+UPDATE feed_follows
+SET feed_id = sqlc.arg(to_feed_id),
+updated_at = current_timestamp
+WHERE feed_id = sqlc.arg(from_feed_id)
+AND user_id NOT IN (
+    SELECT user_id
+    FROM feed_follows
+    WHERE feed_id = sqlc.arg(to_feed_id)
+);
+
+-- name: MoveFeedPosts :exec
+UPDATE posts
+SET feed_id = sqlc.arg(to_feed_id)
+WHERE feed_id = sqlc.arg(from_feed_id);
+
 -- name: GetFeedFollowsForUser :many
-SELECT users.name as user_name, feeds.name as feed_name, users.id as user_id, feeds.id as feed_id
+SELECT users.name as user_name, feeds.name as feed_name, users.id as user_id, feeds.id as feed_id, feeds.url as feed_url, folders.name as folder_name
 FROM users
 INNER JOIN feed_follows
 ON users.id = feed_follows.user_id
 INNER JOIN feeds
 ON feed_follows.feed_id = feeds.id
-WHERE users.name = $1;
+LEFT JOIN folders
+ON feed_follows.folder_id = folders.id
+WHERE users.name = $1
+ORDER BY folders.name ASC NULLS FIRST, feeds.name ASC;
 
 -- name: DeleteFeeds :exec
 DELETE FROM feeds;
 
+-- name: DeletePosts :exec
+DELETE FROM posts;
+
 -- name: DeleteFeedFollowByUrl :exec
 DELETE FROM feed_follows
 WHERE feed_follows.user_id = $1
@@ -77,13 +125,64 @@ updated_at = current_timestamp
 WHERE feeds.id = $1
 RETURNING *;
 
+-- name: RecordFeedFetch :exec
+UPDATE feeds
+SET last_fetch_status = $2,
+last_fetch_error = $3,
+last_fetch_new_posts = $4,
+next_fetch_at = $5,
+updated_at = current_timestamp
+WHERE id = $1;
+
+-- name: SetFeedIsPodcast :exec
+UPDATE feeds
+SET is_podcast = $2,
+updated_at = current_timestamp
+WHERE feeds.id = $1;
+
+-- name: SetFeedAutoDownload :execrows
+UPDATE feeds
+SET auto_download = $2,
+updated_at = current_timestamp
+WHERE feeds.url = $1;
+
+-- name: GetEarliestNextFetch :one
+SELECT next_fetch_at
+FROM feeds
+WHERE next_fetch_at IS NOT NULL
+ORDER BY next_fetch_at ASC
+LIMIT 1;
+
+-- name: PostponeFeedFetch :exec
+UPDATE feeds
+SET next_fetch_at = $2
+WHERE id = $1;
+
+-- name: SetFeedFetchInterval :execrows
+UPDATE feeds
+SET fetch_interval_seconds = $2,
+next_fetch_at = NULL,
+updated_at = current_timestamp
+WHERE url = $1;
+
+-- name: GetFeedPublishTimes :many
+SELECT published_at
+FROM posts
+WHERE feed_id = $1
+AND published_at IS NOT NULL
+AND published_at <> created_at
+ORDER BY published_at DESC
+LIMIT $2;
+
 -- name: GetNextFeedToFetch :one
 SELECT *
 FROM feeds
-ORDER BY last_fetched_at ASC NULLS FIRST;
+WHERE next_fetch_at IS NULL OR next_fetch_at <= $1
+ORDER BY next_fetch_at ASC NULLS FIRST
+LIMIT 1;
 
 -- name: CreatePost :one
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, author)
 VALUES (
     $1,
     $2,
@@ -92,10 +191,19 @@ VALUES (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9
 )
 RETURNING *;
 
+-- name: CreatePostCategory :exec
+INSERT INTO post_categories (post_id, name)
+VALUES (
+    $1,
+    $2
+)
+ON CONFLICT DO NOTHING;
+
 -- name: GetPostsForUser :many
 SELECT posts.*
 FROM posts
@@ -105,6 +213,63 @@ INNER JOIN feed_follows
 ON feeds.id = feed_follows.feed_id
 INNER JOIN users
 ON feed_follows.user_id = users.id
-WHERE users.id = $1
+LEFT JOIN folders
+ON feed_follows.folder_id = folders.id
+WHERE users.id = sqlc.arg(id)
+AND (folders.name = sqlc.arg(folder) OR sqlc.arg(folder) = '')
+AND (feeds.url = sqlc.arg(feed_url) OR sqlc.arg(feed_url) = '')
+AND (lower(posts.author) = lower(sqlc.arg(author)) OR sqlc.arg(author) = '')
+AND (EXISTS (
+    SELECT 1
+    FROM post_categories
+    WHERE post_categories.post_id = posts.id
+    AND lower(post_categories.name) = lower(sqlc.arg(category))
+) OR sqlc.arg(category) = '')
+AND NOT EXISTS (
+    SELECT 1
+    FROM filter_rules
+    INNER JOIN post_filter_targets
+    ON post_filter_targets.field = filter_rules.field
+    WHERE filter_rules.user_id = users.id
+    AND filter_rules.action = 'exclude'
+    AND (filter_rules.feed_id IS NULL OR filter_rules.feed_id = posts.feed_id)
+    AND post_filter_targets.post_id = posts.id
+    AND CASE WHEN filter_rules.is_regex
+        THEN regexp_like(post_filter_targets.value, filter_rules.pattern, 'i')
+        ELSE strpos(lower(post_filter_targets.value), lower(filter_rules.pattern)) > 0
+    END
+)
+AND (NOT EXISTS (
+    SELECT 1
+    FROM filter_rules
+    WHERE filter_rules.user_id = users.id
+    AND filter_rules.action = 'include'
+    AND (filter_rules.feed_id IS NULL OR filter_rules.feed_id = posts.feed_id)
+) OR EXISTS (
+    SELECT 1
+    FROM filter_rules
+    INNER JOIN post_filter_targets
+    ON post_filter_targets.field = filter_rules.field
+    WHERE filter_rules.user_id = users.id
+    AND filter_rules.action = 'include'
+    AND (filter_rules.feed_id IS NULL OR filter_rules.feed_id = posts.feed_id)
+    AND post_filter_targets.post_id = posts.id
+    AND CASE WHEN filter_rules.is_regex
+        THEN regexp_like(post_filter_targets.value, filter_rules.pattern, 'i')
+        ELSE strpos(lower(post_filter_targets.value), lower(filter_rules.pattern)) > 0
+    END
+))
 ORDER BY posts.published_at DESC
+LIMIT sqlc.arg(limit_count);
+
+-- name: GetTopCategoriesForUser :many
+SELECT lower(post_categories.name) AS name, COUNT(*) AS post_count
+FROM post_categories
+INNER JOIN posts
+ON post_categories.post_id = posts.id
+INNER JOIN feed_follows
+ON posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1
+GROUP BY lower(post_categories.name)
+ORDER BY post_count DESC, name ASC
 LIMIT $2;
\ No newline at end of file
@@ -0,0 +1,36 @@
+-- name: CreateFilterRule :one
+INSERT INTO filter_rules (id, created_at, updated_at, user_id, action, field, pattern, is_regex, feed_id)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+)
+RETURNING *;
+
+-- name: GetFilterRulesForUser :many
+SELECT filter_rules.*, feeds.url AS feed_url
+FROM filter_rules
+LEFT JOIN feeds
+ON filter_rules.feed_id = feeds.id
+WHERE filter_rules.user_id = $1
+ORDER BY filter_rules.created_at ASC;
+
+-- name: DeleteFilterRule :execrows
+DELETE FROM filter_rules
+WHERE id = $1
+AND user_id = $2;
+
+-- name: MoveFeedFilterRules :exec
+UPDATE filter_rules
+SET feed_id = sqlc.arg(to_feed_id),
+updated_at = current_timestamp
+WHERE feed_id = sqlc.arg(from_feed_id);
+
+-- name: CheckFilterPattern :one
+SELECT regexp_like('', sqlc.arg(pattern), 'i');
\ No newline at end of file
@@ -0,0 +1,51 @@
+-- name: CreateFolder :one
+INSERT INTO folders (id, created_at, updated_at, user_id, name)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING *;
+
+-- name: GetFolderByName :one
+SELECT *
+FROM folders
+WHERE user_id = $1
+AND name = $2
+LIMIT 1;
+
+-- name: GetFoldersForUser :many
+SELECT *
+FROM folders
+WHERE user_id = $1
+ORDER BY name ASC;
+
+-- name: DeleteFolder :execrows
+DELETE FROM folders
+WHERE user_id = $1
+AND name = $2;
+
+-- name: SetFeedFollowFolder :execrows
+UPDATE feed_follows
+SET folder_id = $1,
+updated_at = current_timestamp
+WHERE feed_follows.user_id = $2
+AND feed_follows.feed_id IN (
+    SELECT id
+    FROM feeds
+    WHERE feeds.url = $3
+);
+
+-- name: RemoveFeedFollowFromFolder :execrows
+UPDATE feed_follows
+SET folder_id = NULL,
+updated_at = current_timestamp
+WHERE feed_follows.user_id = $1
+AND feed_follows.folder_id = $2
+AND feed_follows.feed_id IN (
+    SELECT id
+    FROM feeds
+    WHERE feeds.url = $3
+);
\ No newline at end of file
@@ -1,15 +1,16 @@
 -- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, name)
+INSERT INTO users (id, created_at, updated_at, name, role)
 VALUES (
     $1,
     $2,
     $3,
-    $4
+    $4,
+    $5
 )
 RETURNING *;
 
 -- name: GetUser :one
-SELECT id, created_at, updated_at, name
+SELECT id, created_at, updated_at, name, email, last_digest_at, role
 FROM users
 WHERE name = $1
 LIMIT 1;
@@ -17,6 +18,63 @@ LIMIT 1;
 -- name: DeleteUser :exec
 DELETE FROM users;
 
+-- name: DeleteUserByName :execrows
+DELETE FROM users
+WHERE name = $1;
+
+-- name: RenameUser :execrows
+UPDATE users
+SET name = sqlc.arg(new_name),
+updated_at = current_timestamp
+WHERE name = sqlc.arg(old_name);
+
+-- name: GetUserStats :one
+SELECT
+    (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.user_id = sqlc.arg(user_id)) AS follows,
+    (SELECT COUNT(*) FROM folders WHERE folders.user_id = sqlc.arg(user_id)) AS folders,
+    (SELECT COUNT(*) FROM filter_rules WHERE filter_rules.user_id = sqlc.arg(user_id)) AS filter_rules,
+    (SELECT COUNT(*) FROM webhooks WHERE webhooks.user_id = sqlc.arg(user_id)) AS webhooks,
+    (SELECT COUNT(*)
+        FROM posts
+        INNER JOIN feed_follows
+        ON posts.feed_id = feed_follows.feed_id
+        WHERE feed_follows.user_id = sqlc.arg(user_id)) AS posts,
+    (SELECT COUNT(*)
+        FROM posts
+        INNER JOIN feed_follows
+        ON posts.feed_id = feed_follows.feed_id
+        WHERE feed_follows.user_id = sqlc.arg(user_id)
+        AND posts.created_at >= sqlc.arg(since)) AS recent_posts;
+
 -- name: GetUsers :many
-SELECT id, created_at, updated_at, name
-FROM users;
\ No newline at end of file
+SELECT id, created_at, updated_at, name, email, last_digest_at, role
+FROM users;
+
+-- name: SetUserRole :execrows
+UPDATE users
+SET role = $2,
+updated_at = current_timestamp
+WHERE name = $1;
+
+-- name: CountUsersWithRole :one
+SELECT COUNT(*)
+FROM users
+WHERE role = $1;
+
+-- name: SetUserEmail :exec
+UPDATE users
+SET email = $2,
+updated_at = current_timestamp
+WHERE id = $1;
+
+-- name: MarkUserDigested :exec
+UPDATE users
+SET last_digest_at = $2,
+updated_at = current_timestamp
+WHERE id = $1;
+
+-- name: GetUsersWithEmail :many
+SELECT *
+FROM users
+WHERE email IS NOT NULL
+ORDER BY name ASC;
\ No newline at end of file
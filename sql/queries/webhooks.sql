@@ -0,0 +1,65 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (id, created_at, updated_at, user_id, url, secret, format, batch, feed_id, keyword)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10
+)
+RETURNING *;
+
+-- name: GetWebhooksForUser :many
+SELECT webhooks.*, feeds.url AS feed_url
+FROM webhooks
+LEFT JOIN feeds
+ON webhooks.feed_id = feeds.id
+WHERE webhooks.user_id = $1
+ORDER BY webhooks.created_at ASC;
+
+-- name: GetWebhooksForFeed :many
+SELECT webhooks.*
+FROM webhooks
+INNER JOIN feed_follows
+ON webhooks.user_id = feed_follows.user_id
+WHERE feed_follows.feed_id = $1
+AND (webhooks.feed_id IS NULL OR webhooks.feed_id = $1);
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id = $1
+AND user_id = $2;
+
+-- name: CreateWebhookDelivery :exec
+INSERT INTO webhook_deliveries (id, created_at, webhook_id, post_id, post_count, attempts, status_code, error, delivered)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+);
+
+-- name: GetWebhookDeliveriesForUser :many
+SELECT webhook_deliveries.*, webhooks.url AS webhook_url
+FROM webhook_deliveries
+INNER JOIN webhooks
+ON webhook_deliveries.webhook_id = webhooks.id
+WHERE webhooks.user_id = $1
+ORDER BY webhook_deliveries.created_at DESC
+LIMIT $2;
+
+-- name: MoveFeedWebhooks :exec
+UPDATE webhooks
+SET feed_id = sqlc.arg(to_feed_id),
+updated_at = current_timestamp
+WHERE feed_id = sqlc.arg(from_feed_id);
\ No newline at end of file
@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE posts
+ADD COLUMN author TEXT;
+
+CREATE TABLE post_categories (
+    post_id UUID NOT NULL,
+    CONSTRAINT fk_post_id
+    FOREIGN KEY (post_id)
+    REFERENCES posts(id)
+    ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    PRIMARY KEY (post_id, name)
+);
+
+CREATE INDEX post_categories_lower_name_idx
+ON post_categories (lower(name));
+
+-- +goose Down
+DROP TABLE post_categories;
+
+ALTER TABLE posts
+DROP COLUMN author;
\ No newline at end of file
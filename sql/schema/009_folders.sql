@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE folders (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY (user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    UNIQUE(user_id, name)
+);
+
+ALTER TABLE feed_follows
+ADD COLUMN folder_id UUID;
+
+ALTER TABLE feed_follows
+ADD CONSTRAINT fk_folder_id
+FOREIGN KEY (folder_id)
+REFERENCES folders(id)
+ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE feed_follows
+DROP CONSTRAINT fk_folder_id;
+
+ALTER TABLE feed_follows
+DROP COLUMN folder_id;
+
+DROP TABLE folders;
\ No newline at end of file
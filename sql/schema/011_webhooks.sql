@@ -0,0 +1,46 @@
+-- +goose Up
+CREATE TABLE webhooks (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY (user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT,
+    format TEXT NOT NULL DEFAULT 'json' CHECK (format IN ('json', 'slack', 'discord')),
+    batch BOOLEAN NOT NULL DEFAULT FALSE,
+    feed_id UUID,
+    CONSTRAINT fk_feed_id
+    FOREIGN KEY (feed_id)
+    REFERENCES feeds(id)
+    ON DELETE CASCADE,
+    keyword TEXT
+);
+
+CREATE TABLE webhook_deliveries (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    webhook_id UUID NOT NULL,
+    CONSTRAINT fk_webhook_id
+    FOREIGN KEY (webhook_id)
+    REFERENCES webhooks(id)
+    ON DELETE CASCADE,
+    post_id UUID,
+    CONSTRAINT fk_post_id
+    FOREIGN KEY (post_id)
+    REFERENCES posts(id)
+    ON DELETE SET NULL,
+    post_count INTEGER NOT NULL,
+    attempts INTEGER NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    delivered BOOLEAN NOT NULL
+);
+
+-- +goose Down
+DROP TABLE webhook_deliveries;
+
+DROP TABLE webhooks;
\ No newline at end of file
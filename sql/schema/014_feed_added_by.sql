@@ -0,0 +1,16 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN added_by UUID;
+
+ALTER TABLE feeds
+ADD CONSTRAINT fk_added_by
+FOREIGN KEY (added_by)
+REFERENCES users(id)
+ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP CONSTRAINT fk_added_by;
+
+ALTER TABLE feeds
+DROP COLUMN added_by;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN role TEXT NOT NULL DEFAULT 'member';
+
+-- The oldest account administers existing installations.
+UPDATE users
+SET role = 'admin'
+WHERE id = (SELECT id FROM users ORDER BY created_at LIMIT 1);
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN role;
\ No newline at end of file
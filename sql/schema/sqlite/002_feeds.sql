@@ -0,0 +1,13 @@
+-- +goose Up
+-- The Postgres schema adds feeds.user_id here and drops it again in 003.
+-- SQLite cannot drop a foreign key column, so it is never created.
+CREATE TABLE feeds (
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL UNIQUE
+);
+
+-- +goose Down
+DROP TABLE feeds;
\ No newline at end of file
@@ -0,0 +1,26 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN is_podcast BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE enclosures (
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    post_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    mime_type TEXT NOT NULL,
+    length BIGINT,
+    duration_seconds INTEGER,
+    image_url TEXT,
+    CONSTRAINT fk_post_id
+    FOREIGN KEY (post_id)
+    REFERENCES posts(id)
+    ON DELETE CASCADE,
+    UNIQUE(post_id, url)
+);
+
+-- +goose Down
+DROP TABLE enclosures;
+
+ALTER TABLE feeds
+DROP COLUMN is_podcast;
\ No newline at end of file
@@ -0,0 +1,37 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN auto_download BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE enclosures
+ADD COLUMN local_path TEXT;
+
+ALTER TABLE enclosures
+ADD COLUMN downloaded_bytes BIGINT;
+
+ALTER TABLE enclosures
+ADD COLUMN sha256 TEXT;
+
+ALTER TABLE enclosures
+ADD COLUMN downloaded_at TIMESTAMP;
+
+ALTER TABLE enclosures
+ADD COLUMN played_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE enclosures
+DROP COLUMN played_at;
+
+ALTER TABLE enclosures
+DROP COLUMN downloaded_at;
+
+ALTER TABLE enclosures
+DROP COLUMN sha256;
+
+ALTER TABLE enclosures
+DROP COLUMN downloaded_bytes;
+
+ALTER TABLE enclosures
+DROP COLUMN local_path;
+
+ALTER TABLE feeds
+DROP COLUMN auto_download;
\ No newline at end of file
@@ -0,0 +1,43 @@
+-- +goose Up
+CREATE TABLE filter_rules (
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('include', 'exclude')),
+    field TEXT NOT NULL CHECK (field IN ('title', 'description', 'author', 'category', 'feed')),
+    pattern TEXT NOT NULL,
+    is_regex BOOLEAN NOT NULL DEFAULT FALSE,
+    feed_id TEXT,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY (user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE,
+    CONSTRAINT fk_feed_id
+    FOREIGN KEY (feed_id)
+    REFERENCES feeds(id)
+    ON DELETE CASCADE
+);
+
+CREATE VIEW post_filter_targets AS
+SELECT posts.id AS post_id, 'title' AS field, posts.title AS value
+FROM posts
+UNION ALL
+SELECT posts.id, 'description', COALESCE(posts.description, '')
+FROM posts
+UNION ALL
+SELECT posts.id, 'author', COALESCE(posts.author, '')
+FROM posts
+UNION ALL
+SELECT post_categories.post_id, 'category', post_categories.name
+FROM post_categories
+UNION ALL
+SELECT posts.id, 'feed', feeds.name || ' ' || feeds.url
+FROM posts
+INNER JOIN feeds
+ON posts.feed_id = feeds.id;
+
+-- +goose Down
+DROP VIEW post_filter_targets;
+
+DROP TABLE filter_rules;
\ No newline at end of file
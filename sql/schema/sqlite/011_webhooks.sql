@@ -0,0 +1,46 @@
+-- +goose Up
+CREATE TABLE webhooks (
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT,
+    format TEXT NOT NULL DEFAULT 'json' CHECK (format IN ('json', 'slack', 'discord')),
+    batch BOOLEAN NOT NULL DEFAULT FALSE,
+    feed_id TEXT,
+    keyword TEXT,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY (user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE,
+    CONSTRAINT fk_feed_id
+    FOREIGN KEY (feed_id)
+    REFERENCES feeds(id)
+    ON DELETE CASCADE
+);
+
+CREATE TABLE webhook_deliveries (
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    webhook_id TEXT NOT NULL,
+    post_id TEXT,
+    post_count INTEGER NOT NULL,
+    attempts INTEGER NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    delivered BOOLEAN NOT NULL,
+    CONSTRAINT fk_webhook_id
+    FOREIGN KEY (webhook_id)
+    REFERENCES webhooks(id)
+    ON DELETE CASCADE,
+    CONSTRAINT fk_post_id
+    FOREIGN KEY (post_id)
+    REFERENCES posts(id)
+    ON DELETE SET NULL
+);
+
+-- +goose Down
+DROP TABLE webhook_deliveries;
+
+DROP TABLE webhooks;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN last_fetch_status TEXT;
+
+ALTER TABLE feeds
+ADD COLUMN last_fetch_error TEXT;
+
+ALTER TABLE feeds
+ADD COLUMN last_fetch_new_posts INTEGER;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN last_fetch_new_posts;
+
+ALTER TABLE feeds
+DROP COLUMN last_fetch_error;
+
+ALTER TABLE feeds
+DROP COLUMN last_fetch_status;
\ No newline at end of file
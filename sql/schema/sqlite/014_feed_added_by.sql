@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN added_by TEXT
+REFERENCES users(id)
+ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN added_by;
\ No newline at end of file
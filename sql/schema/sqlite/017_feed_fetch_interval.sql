@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN fetch_interval_seconds INTEGER;
+
+CREATE INDEX feeds_next_fetch_at_idx
+ON feeds (next_fetch_at);
+
+-- +goose Down
+DROP INDEX feeds_next_fetch_at_idx;
+
+ALTER TABLE feeds
+DROP COLUMN fetch_interval_seconds;
\ No newline at end of file
@@ -0,0 +1,9 @@
+// Package sqlite embeds the SQLite versions of the goose migrations. Each
+// file has the same version as its Postgres counterpart in sql/schema and
+// leaves the database in an equivalent state.
+package sqlite
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS